@@ -1,24 +1,54 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/config"
 	"github.com/zully/chess-engine/internal/uci"
 	"github.com/zully/chess-engine/internal/web"
 )
 
+// shutdownTimeout bounds how long shutdown waits for in-flight requests
+const shutdownTimeout = 30 * time.Second
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:]))
+	}
+
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	logLevel, _ := cfg.SlogLevel()
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel})))
+	if cfg.EnableDebug {
+		slog.Info("effective configuration", "config", cfg)
+	}
+
 	// Initialize the game board
 	gameBoard := board.NewBoard()
 
-	// Initialize Stockfish engine (Docker environment)
-	stockfishPath := "/usr/local/bin/stockfish"
+	// Engines the game can switch between; the first is started now, the others on first use
+	engineList := os.Getenv("CHESS_ENGINES")
+	if engineList == "" {
+		engineList = "stockfish=" + cfg.EnginePath
+	}
+	engineSpecs, err := web.ParseEngineSpecs(engineList)
+	if err != nil {
+		log.Fatalf("Invalid CHESS_ENGINES: %v", err)
+	}
 
-	var err error
-	stockfishEngine, err := uci.NewEngine(stockfishPath)
+	stockfishEngine, err := uci.NewEngine(engineSpecs[0].Path)
 	if err != nil {
 		log.Printf("Warning: Failed to initialize Stockfish engine: %v", err)
 		log.Println("Engine features will be disabled")
@@ -26,27 +56,59 @@ func main() {
 
 	// Create web server with dependencies
 	server := web.NewServer(gameBoard, stockfishEngine)
+	server.SetEngines(engineSpecs)
+	server.SetEngineResources(cfg.EngineThreads, cfg.EngineHashMB)
+	server.LegalityCheck = cfg.EnableDebug || os.Getenv("CHESS_LEGALITY_CHECK") == "1"
+	server.AllowPrivateWebhooks = os.Getenv("CHESS_ALLOW_PRIVATE_WEBHOOKS") == "1"
+	server.TemplateDir = cfg.TemplateDir
+	server.StaticDir = cfg.StaticDir
 
-	// Serve static files (CSS, JS)
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("web/static/"))))
-
-	// API endpoints - use server methods
-	http.HandleFunc("/api/state", server.GetGameState)
-	http.HandleFunc("/api/move", server.MakeMove)
-	http.HandleFunc("/api/engine", server.EngineMove)
-	http.HandleFunc("/api/analysis", server.GetEngineAnalysis)
-	http.HandleFunc("/api/undo", server.UndoMove)
-	http.HandleFunc("/api/reset", server.ResetGame)
-
-	// Main page
-	http.HandleFunc("/", server.HomePage)
+	mux := http.NewServeMux()
+	server.SetupRoutes(mux)
+	if cfg.EnableDebug {
+		server.SetupDebugRoutes(mux)
+	}
 
-	fmt.Println("Chess Web GUI with Stockfish starting on http://localhost:8080")
+	fmt.Printf("Chess Web GUI with Stockfish starting on http://localhost:%d\n", cfg.Port)
 	if stockfishEngine != nil {
 		fmt.Println("Stockfish engine initialized successfully")
 	} else {
 		fmt.Println("Running without engine (moves disabled)")
 	}
 
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	// Log every request with its status and timing, recovering from handler panics
+	server.WithMiddleware(web.LoggingMiddleware(slog.Default()), server.RecoveryMiddleware)
+	handler := server.Handler(mux)
+
+	srv := &http.Server{
+		Addr:        fmt.Sprintf(":%d", cfg.Port),
+		Handler:     handler,
+		ReadTimeout: 15 * time.Second,
+	}
+
+	// Stop on SIGTERM (docker stop) or SIGINT, letting in-flight requests finish
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, waiting for in-flight requests", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("http server shutdown", "error", err)
+	}
+
+	slog.Info("stopping engines")
+	server.Close()
+	slog.Info("shutdown complete")
 }
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/config"
+	"github.com/zully/chess-engine/internal/game"
+	"github.com/zully/chess-engine/internal/uci"
+	"github.com/zully/chess-engine/internal/web"
+)
+
+// runReplay implements "chess-engine replay -bundle repro.json": it asks the engine again for
+// every engine move of a bundle downloaded from /api/game/repro and reports the first one it
+// decides differently. It returns the exit code: 0 when the replay matches, 1 on a
+// divergence and 2 when the replay could not run.
+func runReplay(args []string) int {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	bundlePath := flags.String("bundle", "", "reproducibility bundle to replay (JSON from /api/game/repro)")
+	enginePath := flags.String("engine-path", cfg.EnginePath, "path of the engine binary (env CHESS_ENGINE_PATH)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *bundlePath == "" {
+		fmt.Fprintln(os.Stderr, "replay: -bundle is required")
+		flags.Usage()
+		return 2
+	}
+
+	data, err := os.ReadFile(*bundlePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		return 2
+	}
+	var bundle game.ReproBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		fmt.Fprintf(os.Stderr, "replay: parsing %s: %v\n", *bundlePath, err)
+		return 2
+	}
+
+	engine, err := uci.NewEngine(*enginePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		return 2
+	}
+	server := web.NewServer(board.NewBoard(), engine)
+	defer server.Close()
+
+	// The replay searches with one thread so its own searches are reproducible
+	if bundle.Threads > 1 {
+		fmt.Printf("The game was played with %d threads and is replayed with 1; a divergence may only reflect the multi-threaded search\n", bundle.Threads)
+	}
+
+	divergence, err := server.Replay(bundle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		return 2
+	}
+	if divergence != nil {
+		fmt.Printf("Diverged at half-move %d (%s): expected %s, got %s\n",
+			divergence.Ply+1, divergence.FEN, divergence.Expected, divergence.Got)
+		return 1
+	}
+	fmt.Printf("Replayed %d half-moves: the engine decided the same way throughout\n", len(bundle.Moves))
+	return 0
+}
@@ -0,0 +1,76 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String implements fmt.Stringer with the ASCII diagram of the board and the move counters
+func (b *Board) String() string {
+	return b.ToASCII() + fmt.Sprintf("move %d, half-move clock %d\n", b.FullMoveNumber, b.HalfMoveClock)
+}
+
+// GoString implements fmt.GoStringer for %#v: every piece as its constant and square, e.g.
+// "board.WK@e1", from a1 to h8, followed by the FEN of the position
+func (b *Board) GoString() string {
+	var pieces []string
+	for rank := 7; rank >= 0; rank-- {
+		for file := 0; file < 8; file++ {
+			if piece := b.GetPiece(rank, file); piece != Empty {
+				pieces = append(pieces, "board."+PieceToString(piece)+"@"+GetSquareName(rank, file))
+			}
+		}
+	}
+	return fmt.Sprintf("%s [%s]", strings.Join(pieces, " "), b.ToFEN())
+}
+
+// ToASCII returns a diagram of the board from White's side: uppercase letters for White,
+// lowercase for Black and dots for empty squares, followed by the side to move, castling
+// rights and en passant target
+func (b *Board) ToASCII() string {
+	return b.ascii(false)
+}
+
+// ToASCIIFlipped returns the diagram of ToASCII from Black's side
+func (b *Board) ToASCIIFlipped() string {
+	return b.ascii(true)
+}
+
+// ascii draws the board, rank 8 at the top unless flipped
+func (b *Board) ascii(flipped bool) string {
+	var diagram strings.Builder
+
+	for row := 0; row < 8; row++ {
+		rank := row
+		if flipped {
+			rank = 7 - row
+		}
+
+		fmt.Fprintf(&diagram, "%d", 8-rank)
+		for col := 0; col < 8; col++ {
+			file := col
+			if flipped {
+				file = 7 - col
+			}
+
+			symbol := '.'
+			if piece := b.GetPiece(rank, file); piece != Empty {
+				symbol = pieceToFENChar(piece)
+			}
+			fmt.Fprintf(&diagram, " %c", symbol)
+		}
+		diagram.WriteRune('\n')
+	}
+
+	files := "a b c d e f g h"
+	if flipped {
+		files = "h g f e d c b a"
+	}
+	diagram.WriteString("  " + files + "\n")
+
+	// Castling and en passant fields read the same as in FEN
+	fields := strings.Fields(b.ToFEN())
+	fmt.Fprintf(&diagram, "%s to move, castling: %s, en passant: %s\n", b.SideToMove(), fields[2], fields[3])
+
+	return diagram.String()
+}
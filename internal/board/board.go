@@ -32,12 +32,33 @@ type Square struct {
 type Board struct {
 	Squares         [8][8]Square   // 8x8 board with named squares
 	WhiteToMove     bool           // true if it's white's turn
-	CastlingRights  int            // stores castling availability
+	CastlingRights  CastlingRights // stores castling availability
 	EnPassant       string         // en passant target square in algebraic notation
 	HalfMoveClock   int            // counts moves since last pawn move or capture
 	FullMoveNumber  int            // counts full moves in the game
 	MovesPlayed     []string       // list of moves in algebraic notation
+	UCIMoves        []string       // list of moves in UCI notation (parallel to MovesPlayed)
 	PositionHistory map[uint64]int // tracks position occurrences for repetition detection
+	Hash            uint64         // Zobrist hash of the position, updated incrementally
+	CaptureHistory  []CaptureEvent // captures in the order they were made
+	KingSquare      [2][2]int      // rank and file of each king, White first; kept up to date by setPiece
+
+	// FiftyMoveAutomatic makes IsDraw apply the fifty-move rule on its own, as online play does.
+	// When false the draw must be claimed, as under FIDE classical rules.
+	FiftyMoveAutomatic bool
+}
+
+// CaptureEvent records one capture made during the game
+type CaptureEvent struct {
+	CapturedPiece  int    // piece taken off the board
+	CapturingPiece int    // piece that made the capture (the pawn, for a capturing promotion)
+	AtSquare       string // square of the captured piece (the passed pawn's square for en passant)
+	MoveNumber     int    // full move number of the capturing move
+}
+
+// PieceLocation represents a piece and the square it stands on
+type PieceLocation struct {
+	Rank, File, Piece int
 }
 
 // PieceToString converts a piece constant to its string representation
@@ -74,22 +95,88 @@ func PieceToString(piece int) string {
 	}
 }
 
-// GetPieceValue returns the point value of a piece for material calculation
-func GetPieceValue(piece int) int {
+// CentipawnValue returns the material value of a piece in centipawns (the king has no material value)
+func CentipawnValue(piece int) int {
 	switch piece {
 	case WP, BP:
-		return 1
-	case WN, BN, WB, BB:
-		return 3
+		return 100
+	case WN, BN:
+		return 320
+	case WB, BB:
+		return 330
 	case WR, BR:
-		return 5
+		return 500
 	case WQ, BQ:
-		return 9
+		return 900
 	default:
 		return 0
 	}
 }
 
+// GetPieceValue returns the conventional point value of a piece (1/3/3/5/9), derived from CentipawnValue
+func GetPieceValue(piece int) int {
+	return CentipawnValue(piece) / 100
+}
+
+// GetPieceCount returns how many pieces of the given kind (e.g. WN) are on the board
+func (b *Board) GetPieceCount(piece int) int {
+	count := 0
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			if b.Squares[rank][file].Piece == piece {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// GetMaterialBalance returns White's material minus Black's, in centipawns
+func (b *Board) GetMaterialBalance() int {
+	balance := 0
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			piece := b.Squares[rank][file].Piece
+			if piece == Empty {
+				continue
+			}
+			if ColorOf(piece) == White {
+				balance += CentipawnValue(piece)
+			} else {
+				balance -= CentipawnValue(piece)
+			}
+		}
+	}
+	return balance
+}
+
+// File status for a rook, returned by GetRookOpenFileStatus
+const (
+	FileClosed   = 0 // Own pawns on the file
+	FileSemiOpen = 1 // Only opponent pawns on the file
+	FileOpen     = 2 // No pawns on the file
+)
+
+// GetRookOpenFileStatus returns whether a file is open, semi-open or closed for a rook of
+// the given color
+func (b *Board) GetRookOpenFileStatus(file int, color Color) int {
+	ownPawn, opponentPawn := WP, BP
+	if color == Black {
+		ownPawn, opponentPawn = BP, WP
+	}
+
+	status := FileOpen
+	for rank := 0; rank < 8; rank++ {
+		switch b.Squares[rank][file].Piece {
+		case ownPawn:
+			return FileClosed
+		case opponentPawn:
+			status = FileSemiOpen
+		}
+	}
+	return status
+}
+
 // GetPieceType returns the piece type as a single letter (P, N, B, R, Q, K)
 func GetPieceType(piece int) string {
 	switch piece {
@@ -114,12 +201,15 @@ func GetPieceType(piece int) string {
 func NewBoard() *Board {
 	b := &Board{
 		WhiteToMove:     true,
-		CastlingRights:  15, // 1111 in binary - all castling available
+		CastlingRights:  AllCastlingRights,
 		EnPassant:       "", // no en passant target initially
 		HalfMoveClock:   0,
 		FullMoveNumber:  1,
 		MovesPlayed:     make([]string, 0),
+		UCIMoves:        make([]string, 0),
 		PositionHistory: make(map[uint64]int),
+
+		FiftyMoveAutomatic: true,
 	}
 
 	// Initialize all squares with their names
@@ -167,7 +257,9 @@ func NewBoard() *Board {
 	b.Squares[0][6] = Square{Name: "g8", Piece: BN}
 	b.Squares[0][7] = Square{Name: "h8", Piece: BR}
 
-	// Record the initial position
+	// Locate the kings, then hash the initial position and record it
+	b.locateKings()
+	b.Hash = b.computeHash()
 	b.RecordPosition()
 
 	return b
@@ -195,65 +287,119 @@ func (b *Board) GetSquareByCoords(rank, file int) *Square {
 	return &b.Squares[rank][file]
 }
 
+// GetAllPieces returns the location of every piece of the given color
+func (b *Board) GetAllPieces(color Color) []PieceLocation {
+	pieces := make([]PieceLocation, 0, 16)
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			piece := b.GetPiece(rank, file)
+			if piece != Empty && ColorOf(piece) == color {
+				pieces = append(pieces, PieceLocation{Rank: rank, File: file, Piece: piece})
+			}
+		}
+	}
+	return pieces
+}
+
 // IsSquareEmpty returns true if the given square is empty
 func (b *Board) IsSquareEmpty(rank, file int) bool {
 	return b.GetPiece(rank, file) == Empty
 }
 
-// GetPositionHash generates a hash of the current position for repetition detection
+// GetPositionHash returns the hash of the current position for repetition detection
 // Hash includes: piece positions, whose turn, castling rights, en passant target
 func (b *Board) GetPositionHash() uint64 {
-	var hash uint64 = 14695981039346656037 // FNV-1a offset basis
+	return b.Hash
+}
 
-	// Hash piece positions using FNV-1a algorithm (better distribution)
-	const fnvPrime uint64 = 1099511628211
-	for rank := 0; rank < 8; rank++ {
-		for file := 0; file < 8; file++ {
-			piece := b.GetPiece(rank, file)
-			squareIndex := uint64(rank*8 + file)
+// updateMoveClocks advances the halfmove clock and fullmove number for the side about to move
+func (b *Board) updateMoveClocks(isPawnMove, isCapture bool) {
+	// The halfmove clock resets on any pawn move or capture (fifty-move rule)
+	if isPawnMove || isCapture {
+		b.HalfMoveClock = 0
+	} else {
+		b.HalfMoveClock++
+	}
 
-			// Combine piece and square index into a single value
-			value := (uint64(piece) << 8) | squareIndex
+	// The fullmove number increments after Black's move
+	if !b.WhiteToMove {
+		b.FullMoveNumber++
+	}
+}
 
-			// FNV-1a hash
-			hash ^= value
-			hash *= fnvPrime
+// IsTheoreticalDraw reports whether neither side can checkmate by any sequence of legal moves,
+// so the game is drawn at once: bare kings, a single minor piece, or only bishops, all on
+// squares of the same color
+func (b *Board) IsTheoreticalDraw() bool {
+	minors, onlyMinors := b.minorPieces()
+	if !onlyMinors {
+		return false
+	}
+	if len(minors) <= 1 {
+		return true
+	}
+
+	squareColor := (minors[0].Rank + minors[0].File) % 2
+	for _, minor := range minors {
+		if (minor.Piece != WB && minor.Piece != BB) || (minor.Rank+minor.File)%2 != squareColor {
+			return false
 		}
 	}
+	return true
+}
 
-	// Include whose turn it is
-	turnValue := uint64(0)
-	if b.WhiteToMove {
-		turnValue = 1
+// IsDrawishEndgame reports whether the material can't force mate against correct defense,
+// though a mate is still possible: two knights against a bare king, or one bishop each on
+// squares of opposite colors. Unlike IsTheoreticalDraw this doesn't end the game.
+func (b *Board) IsDrawishEndgame() bool {
+	minors, onlyMinors := b.minorPieces()
+	if !onlyMinors || len(minors) != 2 {
+		return false
 	}
-	hash ^= turnValue
-	hash *= fnvPrime
 
-	// Include castling rights
-	hash ^= uint64(b.CastlingRights)
-	hash *= fnvPrime
+	first, second := minors[0], minors[1]
+	twoKnights := (first.Piece == WN && second.Piece == WN) || (first.Piece == BN && second.Piece == BN)
+	bishopEach := (first.Piece == WB && second.Piece == BB) || (first.Piece == BB && second.Piece == WB)
+	oppositeColors := (first.Rank+first.File)%2 != (second.Rank+second.File)%2
+	return twoKnights || (bishopEach && oppositeColors)
+}
 
-	// Include en passant target
-	if b.EnPassant != "" {
-		for _, c := range b.EnPassant {
-			hash ^= uint64(c)
-			hash *= fnvPrime
+// minorPieces returns the knights and bishops on the board. onlyMinors is false if there is
+// also a pawn, rook or queen, which can always make progress.
+func (b *Board) minorPieces() (minors []PieceLocation, onlyMinors bool) {
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			switch piece := b.Squares[rank][file].Piece; piece {
+			case Empty, WK, BK:
+			case WN, BN, WB, BB:
+				minors = append(minors, PieceLocation{Rank: rank, File: file, Piece: piece})
+			default:
+				return nil, false
+			}
 		}
 	}
+	return minors, true
+}
+
+// FiftyMoveCounter returns the half-moves played since the last pawn move or capture
+func (b *Board) FiftyMoveCounter() int {
+	return b.HalfMoveClock
+}
 
-	return hash
+// IsFiftyMoveRule reports whether fifty moves by each side have passed without a pawn move
+// or capture, so the game is drawn (or, without FiftyMoveAutomatic, can be claimed drawn)
+func (b *Board) IsFiftyMoveRule() bool {
+	return b.HalfMoveClock >= 100
 }
 
 // RecordPosition records the current position in history
 func (b *Board) RecordPosition() {
-	hash := b.GetPositionHash()
-	b.PositionHistory[hash]++
+	b.PositionHistory[b.Hash]++
 }
 
 // GetPositionCount returns how many times the current position has occurred
 func (b *Board) GetPositionCount() int {
-	hash := b.GetPositionHash()
-	return b.PositionHistory[hash]
+	return b.PositionHistory[b.Hash]
 }
 
 // IsThreefoldRepetition returns true if current position has occurred 3+ times
@@ -261,71 +407,247 @@ func (b *Board) IsThreefoldRepetition() bool {
 	return b.GetPositionCount() >= 3
 }
 
-// IsDraw returns true if the position is a draw by repetition or stalemate
+// IsDraw returns true if the position is a draw by repetition, the fifty-move rule,
+// impossible mate or stalemate
 func (b *Board) IsDraw() bool {
+	if b.isDrawByRule() {
+		return true
+	}
+
+	// Check for stalemate (no legal moves but not in check)
+	return !b.IsInCheck(b.SideToMove()) && len(b.GetLegalMoves()) == 0
+}
+
+// IsDrawWithLegalMoves is IsDraw for callers that already generated the legal moves of the
+// side to move, so they aren't generated again
+func (b *Board) IsDrawWithLegalMoves(legalMoves []string) bool {
+	if b.isDrawByRule() {
+		return true
+	}
+	return !b.IsInCheck(b.SideToMove()) && len(legalMoves) == 0
+}
+
+// isDrawByRule reports the draws that don't depend on the legal moves: repetition, the
+// automatic fifty-move rule and positions where mate is impossible
+func (b *Board) isDrawByRule() bool {
 	// Check for threefold repetition
 	if b.IsThreefoldRepetition() {
 		return true
 	}
 
-	// Check for stalemate (no legal moves but not in check)
-	if !b.IsInCheck(b.WhiteToMove) {
-		// Generate all legal moves to see if there are any
-		// This is a simplified check - ideally we'd use the move generator
-		hasLegalMove := false
-
-		// Quick check: try to find at least one legal move
-		for fromRank := 0; fromRank < 8 && !hasLegalMove; fromRank++ {
-			for fromFile := 0; fromFile < 8 && !hasLegalMove; fromFile++ {
-				piece := b.GetPiece(fromRank, fromFile)
-
-				// Skip empty squares and opponent pieces
-				if piece == Empty || (piece < BP) != b.WhiteToMove {
-					continue
-				}
+	// Check for the fifty-move rule, unless it has to be claimed
+	if b.FiftyMoveAutomatic && b.IsFiftyMoveRule() {
+		return true
+	}
+
+	// Check for positions where mate is impossible
+	return b.IsTheoreticalDraw()
+}
 
-				// Try a few potential moves for this piece
-				for toRank := 0; toRank < 8 && !hasLegalMove; toRank++ {
-					for toFile := 0; toFile < 8 && !hasLegalMove; toFile++ {
-						if fromRank == toRank && fromFile == toFile {
-							continue
-						}
-
-						// Test if this would be a legal move (simplified test)
-						targetPiece := b.GetPiece(toRank, toFile)
-						if targetPiece != Empty && (targetPiece < BP) == b.WhiteToMove {
-							continue // Can't capture own piece
-						}
-
-						// Try the move temporarily
-						b.Squares[toRank][toFile].Piece = piece
-						b.Squares[fromRank][fromFile].Piece = Empty
-
-						// Check if still in check after move
-						stillInCheck := b.IsInCheck(b.WhiteToMove)
-
-						// Undo the move
-						b.Squares[fromRank][fromFile].Piece = piece
-						b.Squares[toRank][toFile].Piece = targetPiece
-
-						if !stillInCheck {
-							hasLegalMove = true
-						}
-					}
+// Validate checks that the position is consistent and could occur in a legal game
+func (b *Board) Validate() error {
+	whiteKings, blackKings := 0, 0
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			piece := b.GetPiece(rank, file)
+			if piece < Empty || piece > BK {
+				return fmt.Errorf("invalid piece %d on %s", piece, GetSquareName(rank, file))
+			}
+			switch piece {
+			case WK:
+				whiteKings++
+			case BK:
+				blackKings++
+			case WP, BP:
+				if rank == 0 || rank == 7 {
+					return fmt.Errorf("pawn on back rank at %s", GetSquareName(rank, file))
 				}
 			}
 		}
+	}
+
+	if whiteKings != 1 || blackKings != 1 {
+		return fmt.Errorf("expected one king per side, found %d white and %d black", whiteKings, blackKings)
+	}
+
+	// The side that just moved can't have left its king in check
+	if b.IsInCheck(b.SideToMove().Opposite()) {
+		return fmt.Errorf("side not to move is in check")
+	}
 
-		if !hasLegalMove {
-			return true // Stalemate
+	if b.Hash != b.computeHash() {
+		return fmt.Errorf("position hash is out of date")
+	}
+	for _, color := range []Color{White, Black} {
+		kingRank, kingFile := b.scanForKing(color)
+		if cachedRank, cachedFile := b.GetKingSquare(color == White); cachedRank != kingRank || cachedFile != kingFile {
+			return fmt.Errorf("%s king square is out of date", color)
 		}
 	}
 
-	return false
+	return nil
+}
+
+// Clone returns a deep copy of the board
+func (b *Board) Clone() *Board {
+	clone := *b
+
+	clone.MovesPlayed = append([]string(nil), b.MovesPlayed...)
+	clone.UCIMoves = append([]string(nil), b.UCIMoves...)
+	clone.CaptureHistory = append([]CaptureEvent(nil), b.CaptureHistory...)
+	clone.PositionHistory = make(map[uint64]int, len(b.PositionHistory))
+	for hash, count := range b.PositionHistory {
+		clone.PositionHistory[hash] = count
+	}
+
+	return &clone
+}
+
+// GetCaptureHistory returns the captures made so far, in order
+func (b *Board) GetCaptureHistory() []CaptureEvent {
+	return append([]CaptureEvent(nil), b.CaptureHistory...)
+}
+
+// recordCapture adds a capture by the side to move to the capture history
+func (b *Board) recordCapture(captured, capturing int, square string) {
+	b.CaptureHistory = append(b.CaptureHistory, CaptureEvent{
+		CapturedPiece:  captured,
+		CapturingPiece: capturing,
+		AtSquare:       square,
+		MoveNumber:     b.FullMoveNumber,
+	})
+}
+
+// kingSafetyError describes a move that leaves the mover's own king in check
+func kingSafetyError(wasInCheck bool) error {
+	if wasInCheck {
+		return fmt.Errorf("must respond to check")
+	}
+	return fmt.Errorf("move would put king in check")
+}
+
+// positionState is the part of the board a move changes before it can still fail: the pieces
+// and the scalar fields. The histories are only appended to once a move has succeeded.
+type positionState struct {
+	squares        [8][8]Square
+	whiteToMove    bool
+	castlingRights CastlingRights
+	enPassant      string
+	halfMoveClock  int
+	fullMoveNumber int
+	hash           uint64
+	kingSquare     [2][2]int
+}
+
+// savePosition snapshots the position so a failed move can be undone with restorePosition
+func (b *Board) savePosition() positionState {
+	return positionState{
+		squares:        b.Squares,
+		whiteToMove:    b.WhiteToMove,
+		castlingRights: b.CastlingRights,
+		enPassant:      b.EnPassant,
+		halfMoveClock:  b.HalfMoveClock,
+		fullMoveNumber: b.FullMoveNumber,
+		hash:           b.Hash,
+		kingSquare:     b.KingSquare,
+	}
+}
+
+// restorePosition puts back a position saved with savePosition
+func (b *Board) restorePosition(state positionState) {
+	b.Squares = state.squares
+	b.WhiteToMove = state.whiteToMove
+	b.CastlingRights = state.castlingRights
+	b.EnPassant = state.enPassant
+	b.HalfMoveClock = state.halfMoveClock
+	b.FullMoveNumber = state.fullMoveNumber
+	b.Hash = state.hash
+	b.KingSquare = state.kingSquare
 }
 
 // MakeUCIMove makes a move on the board using UCI notation (e.g., "e2e4", "a1h8")
-func (b *Board) MakeUCIMove(uciMove string) error {
+func (b *Board) MakeUCIMove(uciMove string) (err error) {
+	// Illegal moves leave the board untouched: the position is put back if the move fails
+	saved := b.savePosition()
+	defer func() {
+		if err != nil {
+			b.restorePosition(saved)
+		}
+	}()
+	return b.makeUCIMove(uciMove)
+}
+
+// ApplyMoves plays a sequence of UCI moves and returns how many were played. It stops at the
+// first illegal move, leaving the moves before it on the board.
+func (b *Board) ApplyMoves(uciMoves []string) (int, error) {
+	for i, uciMove := range uciMoves {
+		if err := b.MakeUCIMove(uciMove); err != nil {
+			return i, fmt.Errorf("move %d (%s) in position %s: %v", i+1, uciMove, b.ToFEN(), err)
+		}
+	}
+	return len(uciMoves), nil
+}
+
+// ApplyMovesFromSAN is ApplyMoves for moves in algebraic notation, e.g. from a PGN
+func (b *Board) ApplyMovesFromSAN(sans []string) (int, error) {
+	for i, san := range sans {
+		if err := b.MakeMove(san); err != nil {
+			return i, fmt.Errorf("move %d (%s) in position %s: %v", i+1, san, b.ToFEN(), err)
+		}
+	}
+	return len(sans), nil
+}
+
+// MoveError describes a move of a list that could not be played
+type MoveError struct {
+	Index int    `json:"index"` // Position of the move in the list, from 0
+	Move  string `json:"move"`
+	Error string `json:"error"`
+	FEN   string `json:"fen"` // Position the move was tried in
+}
+
+// ValidateMoveList plays a list of moves, in UCI or algebraic notation, on a copy of the board
+// and reports every move that fails rather than stopping at the first. A failed move is skipped
+// by passing the turn, so the moves after it are still tried by the right side. When the side
+// to move is in check it can't pass, so the list is only checked up to that failure.
+func (b *Board) ValidateMoveList(moves []string) (validCount int, moveErrors []MoveError) {
+	replay := b.Clone()
+	for i, move := range moves {
+		var err error
+		if isUCIMoveFormat(move) {
+			err = replay.MakeUCIMove(move)
+		} else {
+			err = replay.MakeMove(move)
+		}
+
+		if err != nil {
+			moveErrors = append(moveErrors, MoveError{Index: i, Move: move, Error: err.Error(), FEN: replay.ToFEN()})
+			if replay.IsInCheck(replay.SideToMove()) {
+				break
+			}
+			replay.MakeNullMove()
+			continue
+		}
+		validCount++
+	}
+	return validCount, moveErrors
+}
+
+// isUCIMoveFormat reports whether a move is written as two squares, with an optional promotion
+func isUCIMoveFormat(move string) bool {
+	if len(move) != 4 && len(move) != 5 {
+		return false
+	}
+	for _, square := range []string{move[0:2], move[2:4]} {
+		if square[0] < 'a' || square[0] > 'h' || square[1] < '1' || square[1] > '8' {
+			return false
+		}
+	}
+	return true
+}
+
+// makeUCIMove applies a UCI move with all of its side effects
+func (b *Board) makeUCIMove(uciMove string) error {
 	if len(uciMove) < 4 || len(uciMove) > 5 {
 		return fmt.Errorf("invalid UCI move format: %s", uciMove)
 	}
@@ -338,6 +660,9 @@ func (b *Board) MakeUCIMove(uciMove string) error {
 	var promotionPiece string
 	if len(uciMove) == 5 {
 		promotionPiece = strings.ToUpper(string(uciMove[4]))
+		if !strings.Contains("QRBN", promotionPiece) {
+			return fmt.Errorf("invalid promotion piece: %s", uciMove[4:])
+		}
 	}
 
 	// Get coordinates
@@ -365,42 +690,67 @@ func (b *Board) MakeUCIMove(uciMove string) error {
 	}
 
 	// Check that the piece belongs to the current player
-	isWhitePiece := fromSquareObj.Piece < BP
-	if b.WhiteToMove != isWhitePiece {
+	pieceColor := ColorOf(fromSquareObj.Piece)
+	if b.SideToMove() != pieceColor {
 		return fmt.Errorf("not your piece to move")
 	}
 
+	// A promotion piece is only valid on a pawn move to the last rank
+	reachesLastRank := (fromSquareObj.Piece == WP && toRank == 0) || (fromSquareObj.Piece == BP && toRank == 7)
+	if promotionPiece != "" && !reachesLastRank {
+		return fmt.Errorf("only a pawn reaching the last rank can promote: %s", uciMove)
+	}
+	if reachesLastRank {
+		// Promote to a queen by default, and record the move in canonical UCI form
+		if promotionPiece == "" {
+			promotionPiece = "Q"
+		}
+		uciMove = uciMove[:4] + strings.ToLower(promotionPiece)
+	}
+
 	// Handle castling moves specially
 	if fromSquareObj.Piece == WK || fromSquareObj.Piece == BK {
 		// Check for castling
 		if b.WhiteToMove && fromSquare == "e1" {
-			if toSquare == "g1" && b.canCastle("O-O", true) {
-				b.executeCastling("O-O", true)
-				b.WhiteToMove = false
+			if toSquare == "g1" && b.canCastle("O-O", White) {
+				b.executeCastling("O-O", White)
+				b.setEnPassant("")
+				b.updateMoveClocks(false, false)
+				b.switchSide()
 				b.RecordPosition()
-				b.MovesPlayed = append(b.MovesPlayed, "O-O")
+				b.MovesPlayed = append(b.MovesPlayed, "O-O"+b.checkSuffix())
+				b.UCIMoves = append(b.UCIMoves, uciMove)
 				return nil
 			}
-			if toSquare == "c1" && b.canCastle("O-O-O", true) {
-				b.executeCastling("O-O-O", true)
-				b.WhiteToMove = false
+			if toSquare == "c1" && b.canCastle("O-O-O", White) {
+				b.executeCastling("O-O-O", White)
+				b.setEnPassant("")
+				b.updateMoveClocks(false, false)
+				b.switchSide()
 				b.RecordPosition()
-				b.MovesPlayed = append(b.MovesPlayed, "O-O-O")
+				b.MovesPlayed = append(b.MovesPlayed, "O-O-O"+b.checkSuffix())
+				b.UCIMoves = append(b.UCIMoves, uciMove)
 				return nil
 			}
 		} else if !b.WhiteToMove && fromSquare == "e8" {
-			if toSquare == "g8" && b.canCastle("O-O", false) {
-				b.executeCastling("O-O", false)
-				b.WhiteToMove = true
+			if toSquare == "g8" && b.canCastle("O-O", Black) {
+				b.executeCastling("O-O", Black)
+				b.setEnPassant("")
+				b.updateMoveClocks(false, false)
+				b.switchSide()
 				b.RecordPosition()
-				b.MovesPlayed = append(b.MovesPlayed, "O-O")
+				b.MovesPlayed = append(b.MovesPlayed, "O-O"+b.checkSuffix())
+				b.UCIMoves = append(b.UCIMoves, uciMove)
 				return nil
 			}
-			if toSquare == "c8" && b.canCastle("O-O-O", false) {
-				b.executeCastling("O-O-O", false)
-				b.WhiteToMove = true
+			if toSquare == "c8" && b.canCastle("O-O-O", Black) {
+				b.executeCastling("O-O-O", Black)
+				b.setEnPassant("")
+				b.updateMoveClocks(false, false)
+				b.switchSide()
 				b.RecordPosition()
-				b.MovesPlayed = append(b.MovesPlayed, "O-O-O")
+				b.MovesPlayed = append(b.MovesPlayed, "O-O-O"+b.checkSuffix())
+				b.UCIMoves = append(b.UCIMoves, uciMove)
 				return nil
 			}
 		}
@@ -410,54 +760,36 @@ func (b *Board) MakeUCIMove(uciMove string) error {
 	piece := fromSquareObj.Piece
 	isCapture := toSquareObj.Piece != Empty
 
+	// A pawn moving diagonally onto the en passant square captures en passant
+	enPassant := b.isEnPassantCapture(fromSquare, toSquare)
+	if enPassant {
+		isCapture = true
+	}
+
 	if !b.isValidMove(piece, fromRank, fromFile, toRank, toFile, isCapture) {
 		return fmt.Errorf("illegal move for piece")
 	}
 
 	// Check if moving would capture own piece
 	if toSquareObj.Piece != Empty {
-		targetIsWhite := toSquareObj.Piece < BP
-		if isWhitePiece == targetIsWhite {
+		if ColorOf(toSquareObj.Piece) == pieceColor {
 			return fmt.Errorf("cannot capture your own piece")
 		}
 	}
 
-	// If the current player is in check, verify that this move gets them out of check
-	currentPlayerIsWhite := b.WhiteToMove
-	if b.IsInCheck(currentPlayerIsWhite) {
-		// Try the move temporarily
-		originalToPiece := toSquareObj.Piece
-		toSquareObj.Piece = piece
-		fromSquareObj.Piece = Empty
-
-		stillInCheck := b.IsInCheck(currentPlayerIsWhite)
-
-		// Undo the temporary move
-		fromSquareObj.Piece = piece
-		toSquareObj.Piece = originalToPiece
-
-		if stillInCheck {
-			return fmt.Errorf("must respond to check")
-		}
-	}
+	mover := b.SideToMove()
+	wasInCheck := b.IsInCheck(mover)
 
 	// Convert UCI to algebraic BEFORE making the move (so we can still see the piece)
 	algebraicMove := b.uciToAlgebraic(uciMove)
 
-	// Store the original target piece for potential restoration
+	// Remember whether this move captures, for the fifty-move clock
 	originalTargetPiece := toSquareObj.Piece
+	capturedPiece, capturedSquare := originalTargetPiece, toSquare
 
 	// Execute the move
-	b.Squares[toRank][toFile].Piece = piece
-	b.Squares[fromRank][fromFile].Piece = Empty
-
-	// Verify that this move doesn't put our own king in check
-	if b.IsInCheck(currentPlayerIsWhite) {
-		// Undo the move
-		b.Squares[fromRank][fromFile].Piece = piece
-		b.Squares[toRank][toFile].Piece = originalTargetPiece
-		return fmt.Errorf("move would put king in check")
-	}
+	b.setPiece(toRank, toFile, piece)
+	b.setPiece(fromRank, fromFile, Empty)
 
 	// Handle pawn promotion
 	if (piece == WP && toRank == 0) || (piece == BP && toRank == 7) {
@@ -488,47 +820,55 @@ func (b *Board) MakeUCIMove(uciMove string) error {
 				newPiece = BN
 			}
 		default:
-			// Default to Queen if no promotion piece specified
 			if piece == WP {
 				newPiece = WQ
 			} else {
 				newPiece = BQ
 			}
 		}
-		b.Squares[toRank][toFile].Piece = newPiece
+		b.setPiece(toRank, toFile, newPiece)
 	}
 
 	// Handle en passant capture
-	if (piece == WP || piece == BP) && isCapture && b.EnPassant == toSquare {
-		// Remove the captured pawn
-		capturedPawnRank := toRank
-		if piece == WP {
-			capturedPawnRank = toRank + 1
-		} else {
-			capturedPawnRank = toRank - 1
-		}
-		b.Squares[capturedPawnRank][toFile].Piece = Empty
+	if enPassant {
+		// Remove the captured pawn, just behind the target square
+		capturedPawnRank := toRank - ColorOf(piece).PawnDirection()
+		capturedPiece, capturedSquare = b.GetPiece(capturedPawnRank, toFile), GetSquareName(capturedPawnRank, toFile)
+		b.setPiece(capturedPawnRank, toFile, Empty)
+	}
+
+	// With every piece in place (including a pawn removed en passant), the king must be safe
+	if b.IsInCheck(mover) {
+		return kingSafetyError(wasInCheck)
+	}
+
+	if capturedPiece != Empty {
+		b.recordCapture(capturedPiece, piece, capturedSquare)
 	}
 
 	// Handle en passant target setting
 	if (piece == WP || piece == BP) && abs(toRank-fromRank) == 2 {
 		targetRank := (fromRank + toRank) / 2
-		b.EnPassant = GetSquareName(targetRank, toFile)
+		b.setEnPassant(GetSquareName(targetRank, toFile))
 	} else {
-		b.EnPassant = ""
+		b.setEnPassant("")
 	}
 
-	// Update castling rights
-	b.updateCastlingRights(fromSquare, piece)
+	// Update castling rights if king or rook moves, or a rook is captured
+	b.updateCastlingRights(fromSquare, piece, toSquare, originalTargetPiece)
+
+	// Update the fifty-move clock and move number
+	b.updateMoveClocks(piece == WP || piece == BP, originalTargetPiece != Empty)
 
 	// Switch turns
-	b.WhiteToMove = !b.WhiteToMove
+	b.switchSide()
 
 	// Record position for repetition detection
 	b.RecordPosition()
 
-	// Add to move history
-	b.MovesPlayed = append(b.MovesPlayed, algebraicMove)
+	// Add to move history (with check notation if applicable)
+	b.MovesPlayed = append(b.MovesPlayed, algebraicMove+b.checkSuffix())
+	b.UCIMoves = append(b.UCIMoves, uciMove)
 
 	return nil
 }
@@ -553,6 +893,16 @@ func (b *Board) isValidMove(piece int, fromRank, fromFile, toRank, toFile int, i
 	}
 }
 
+// SAN returns the standard algebraic notation of a UCI move in the current position,
+// including disambiguation and the check or mate suffix. The board is not modified.
+func (b *Board) SAN(uciMove string) (string, error) {
+	next := b.Clone()
+	if err := next.makeUCIMove(uciMove); err != nil {
+		return "", err
+	}
+	return next.MovesPlayed[len(next.MovesPlayed)-1], nil
+}
+
 // uciToAlgebraic converts UCI move to algebraic notation for move history display
 func (b *Board) uciToAlgebraic(uciMove string) string {
 	// For now, return a simplified algebraic notation
@@ -565,14 +915,6 @@ func (b *Board) uciToAlgebraic(uciMove string) string {
 	fromSquare := uciMove[0:2]
 	toSquare := uciMove[2:4]
 
-	// Handle castling
-	if uciMove == "e1g1" || uciMove == "e8g8" {
-		return "O-O"
-	}
-	if uciMove == "e1c1" || uciMove == "e8c8" {
-		return "O-O-O"
-	}
-
 	// Get piece type from the from square
 	fromRank, fromFile := GetSquareCoords(fromSquare)
 	if fromRank < 0 || fromFile < 0 || fromRank > 7 || fromFile > 7 {
@@ -584,6 +926,16 @@ func (b *Board) uciToAlgebraic(uciMove string) string {
 		return uciMove
 	}
 
+	// Handle castling (only a king can castle - a rook on e1 may also move to g1)
+	if piece == WK || piece == BK {
+		if uciMove == "e1g1" || uciMove == "e8g8" {
+			return "O-O"
+		}
+		if uciMove == "e1c1" || uciMove == "e8c8" {
+			return "O-O-O"
+		}
+	}
+
 	pieceType := GetPieceType(piece)
 
 	// For pawns, just return the target square (or capture notation)
@@ -645,12 +997,15 @@ func (b *Board) getDisambiguation(piece int, fromRank, fromFile, toRank, toFile
 				continue // Skip the piece we're moving
 			}
 
+			// Compare against the actual board contents - after promotion there may be
+			// several queens (or three knights) that can reach the same square
 			boardPiece := b.GetPiece(rank, file)
 			if boardPiece == piece {
-				// Check if this piece could legally move to the target square
+				// Check if this piece could legally move to the target square (pinned pieces can't)
 				targetPiece := b.GetPiece(toRank, toFile)
 				isCapture := targetPiece != Empty
-				if b.isValidMove(boardPiece, rank, file, toRank, toFile, isCapture) {
+				if b.isValidMove(boardPiece, rank, file, toRank, toFile, isCapture) &&
+					!b.leavesKingInCheck(rank, file, toRank, toFile) {
 					sameTypePieces = append(sameTypePieces, struct{ rank, file int }{rank, file})
 				}
 			}
@@ -691,3 +1046,54 @@ func (b *Board) getDisambiguation(piece int, fromRank, fromFile, toRank, toFile
 	// If neither file nor rank alone is sufficient, use both
 	return string(rune('a'+fromFile)) + string(rune('1'+(7-fromRank)))
 }
+
+// leavesKingInCheck reports whether moving the piece would leave its own king in check
+func (b *Board) leavesKingInCheck(fromRank, fromFile, toRank, toFile int) bool {
+	piece := b.Squares[fromRank][fromFile].Piece
+	originalPiece := b.Squares[toRank][toFile].Piece
+
+	// Try the move temporarily
+	b.Squares[toRank][toFile].Piece = piece
+	b.Squares[fromRank][fromFile].Piece = Empty
+
+	inCheck := b.IsInCheck(ColorOf(piece))
+
+	// Undo the move
+	b.Squares[fromRank][fromFile].Piece = piece
+	b.Squares[toRank][toFile].Piece = originalPiece
+
+	return inCheck
+}
+
+// checkSuffix returns the SAN suffix for the side to move: "#" if mated, "+" if in check
+func (b *Board) checkSuffix() string {
+	if !b.IsInCheck(b.SideToMove()) {
+		return ""
+	}
+	if b.IsCheckmate(b.SideToMove()) {
+		return "#"
+	}
+	return "+"
+}
+
+// VerifyHistory replays the recorded SAN history from the starting position and checks
+// that every move regenerates the same SAN and UCI notation
+func (b *Board) VerifyHistory() error {
+	if len(b.MovesPlayed) != len(b.UCIMoves) {
+		return fmt.Errorf("history has %d SAN moves but %d UCI moves", len(b.MovesPlayed), len(b.UCIMoves))
+	}
+
+	replay := NewBoard()
+	for i, san := range b.MovesPlayed {
+		if err := replay.MakeMove(san); err != nil {
+			return fmt.Errorf("move %d (%s): %v", i+1, san, err)
+		}
+		if replay.MovesPlayed[i] != san {
+			return fmt.Errorf("move %d: recorded as %s but replays as %s", i+1, san, replay.MovesPlayed[i])
+		}
+		if replay.UCIMoves[i] != b.UCIMoves[i] {
+			return fmt.Errorf("move %d (%s): recorded as %s but replays as %s", i+1, san, b.UCIMoves[i], replay.UCIMoves[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTheoreticalDraws(t *testing.T) {
+	tests := []struct {
+		name        string
+		fen         string
+		theoretical bool // Mate impossible: drawn at once
+		drawish     bool // Mate possible but not forced: play goes on
+	}{
+		{"bare kings", "8/8/4k3/8/8/3K4/8/8 w - - 0 1", true, false},
+		{"king and knight", "8/8/4k3/8/8/3K4/5N2/8 w - - 0 1", true, false},
+		{"king and bishop", "8/8/4k3/8/8/3K4/8/2b5 b - - 0 1", true, false},
+		{"same-colored bishops", "8/8/4k3/2b5/8/3K4/8/2B5 w - - 0 1", true, false},
+		{"two same-colored bishops", "8/8/4k3/8/8/3K4/8/B1B5 w - - 0 1", true, false},
+		{"opposite-colored bishops", "8/8/4k3/1b6/8/3K4/8/2B5 w - - 0 1", false, true},
+		{"two knights", "8/8/4k3/8/8/3K4/5N2/6N1 w - - 0 1", false, true},
+		{"knight each", "8/8/4k3/8/5n2/3K4/5N2/8 w - - 0 1", false, false},
+		{"bishop pair", "8/8/4k3/8/8/3K4/8/2B2B2 w - - 0 1", false, false},
+		{"bishop and knight", "8/8/4k3/8/8/3K4/5N2/2B5 w - - 0 1", false, false},
+		{"pawn", "8/8/4k3/8/8/3K4/4P3/8 w - - 0 1", false, false},
+		{"rook", "8/8/4k3/8/8/3K4/8/7R w - - 0 1", false, false},
+	}
+
+	for _, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := b.IsTheoreticalDraw(); got != test.theoretical {
+			t.Errorf("%s: IsTheoreticalDraw() = %v, want %v", test.name, got, test.theoretical)
+		}
+		if got := b.IsDrawishEndgame(); got != test.drawish {
+			t.Errorf("%s: IsDrawishEndgame() = %v, want %v", test.name, got, test.drawish)
+		}
+		if got := b.IsDraw(); got != test.theoretical {
+			t.Errorf("%s: IsDraw() = %v, want %v", test.name, got, test.theoretical)
+		}
+	}
+}
+
+func TestValidateMoveList(t *testing.T) {
+	tests := []struct {
+		name       string
+		moves      []string
+		wantValid  int
+		wantErrors []int // Indexes of the failing moves
+	}{
+		{"all valid", []string{"e4", "e5", "Nf3", "Nc6", "Bb5"}, 5, nil},
+		// Nc5 is a misread Nc6: the moves after it are still played by the right side
+		{"one bad move", []string{"e4", "e5", "Nf3", "Nc5", "Bb5", "a6", "Ba4", "Nf6", "O-O"}, 8, []int{3}},
+		{"bad moves for both sides", []string{"e2e4", "e7e5", "g1f4", "b8c6", "f1c4", "g8g6", "d2d3"}, 5, []int{2, 5}},
+		// Black in check can't pass the turn, so checking stops at the failure
+		{"bad move in check", []string{"e4", "f5", "Qh5+", "Nf6", "g6", "Qxg6+"}, 3, []int{3}},
+	}
+
+	for _, tt := range tests {
+		validCount, moveErrors := NewBoard().ValidateMoveList(tt.moves)
+		if validCount != tt.wantValid {
+			t.Errorf("%s: %d valid moves, want %d (errors %+v)", tt.name, validCount, tt.wantValid, moveErrors)
+		}
+
+		var indexes []int
+		for _, moveError := range moveErrors {
+			indexes = append(indexes, moveError.Index)
+		}
+		if !reflect.DeepEqual(indexes, tt.wantErrors) {
+			t.Errorf("%s: errors at %v, want %v (%+v)", tt.name, indexes, tt.wantErrors, moveErrors)
+		}
+	}
+}
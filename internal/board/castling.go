@@ -0,0 +1,108 @@
+package board
+
+// CastlingRights tells which castling moves each side is still entitled to make, whether or
+// not they are legal in the current position
+type CastlingRights struct {
+	WhiteKingside  bool
+	WhiteQueenside bool
+	BlackKingside  bool
+	BlackQueenside bool
+}
+
+// AllCastlingRights is the castling availability of the starting position
+var AllCastlingRights = CastlingRights{WhiteKingside: true, WhiteQueenside: true, BlackKingside: true, BlackQueenside: true}
+
+// ToInt returns the rights as a bitmask: 1 for White kingside, 2 for White queenside,
+// 4 for Black kingside and 8 for Black queenside
+func (cr CastlingRights) ToInt() int {
+	rights := 0
+	if cr.WhiteKingside {
+		rights |= 1
+	}
+	if cr.WhiteQueenside {
+		rights |= 2
+	}
+	if cr.BlackKingside {
+		rights |= 4
+	}
+	if cr.BlackQueenside {
+		rights |= 8
+	}
+	return rights
+}
+
+// FromIntCastling converts a bitmask in the format of ToInt to castling rights
+func FromIntCastling(rights int) CastlingRights {
+	return CastlingRights{
+		WhiteKingside:  rights&1 != 0,
+		WhiteQueenside: rights&2 != 0,
+		BlackKingside:  rights&4 != 0,
+		BlackQueenside: rights&8 != 0,
+	}
+}
+
+// String returns the rights as in FEN, e.g. "KQkq", or "-" when neither side may castle
+func (cr CastlingRights) String() string {
+	castling := ""
+	if cr.WhiteKingside {
+		castling += "K"
+	}
+	if cr.WhiteQueenside {
+		castling += "Q"
+	}
+	if cr.BlackKingside {
+		castling += "k"
+	}
+	if cr.BlackQueenside {
+		castling += "q"
+	}
+	if castling == "" {
+		return "-"
+	}
+	return castling
+}
+
+// Kingside reports whether the color may still castle kingside
+func (cr CastlingRights) Kingside(color Color) bool {
+	if color == White {
+		return cr.WhiteKingside
+	}
+	return cr.BlackKingside
+}
+
+// Queenside reports whether the color may still castle queenside
+func (cr CastlingRights) Queenside(color Color) bool {
+	if color == White {
+		return cr.WhiteQueenside
+	}
+	return cr.BlackQueenside
+}
+
+// removeForHomeSquare clears the rights that depend on the piece standing on its home square.
+// The piece must match the square, so e.g. a black king on e1 leaves White's rights alone.
+func (cr *CastlingRights) removeForHomeSquare(square string, piece int) {
+	switch {
+	case square == "e1" && piece == WK:
+		cr.WhiteKingside, cr.WhiteQueenside = false, false
+	case square == "a1" && piece == WR:
+		cr.WhiteQueenside = false
+	case square == "h1" && piece == WR:
+		cr.WhiteKingside = false
+	case square == "e8" && piece == BK:
+		cr.BlackKingside, cr.BlackQueenside = false, false
+	case square == "a8" && piece == BR:
+		cr.BlackQueenside = false
+	case square == "h8" && piece == BR:
+		cr.BlackKingside = false
+	}
+}
+
+// intersect keeps only the rights present in both
+func (cr CastlingRights) intersect(other CastlingRights) CastlingRights {
+	return CastlingRights{
+		WhiteKingside:  cr.WhiteKingside && other.WhiteKingside,
+		WhiteQueenside: cr.WhiteQueenside && other.WhiteQueenside,
+		BlackKingside:  cr.BlackKingside && other.BlackKingside,
+		BlackQueenside: cr.BlackQueenside && other.BlackQueenside,
+	}
+}
@@ -0,0 +1,30 @@
+package board
+
+import "testing"
+
+func TestCastlingRightsAfterRookCapture(t *testing.T) {
+	tests := []struct {
+		name, fen, move, wantRights string
+	}{
+		{"bishop takes h1 rook", "r3k2r/8/8/8/8/8/6b1/R3K2R b KQkq - 0 1", "g2h1", "Qkq"},
+		{"knight takes a1 rook", "r3k2r/8/8/8/8/1n6/8/R3K2R b KQkq - 0 1", "b3a1", "Kkq"},
+		{"rook takes a8 rook", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "a1a8", "Kk"},
+		{"rook takes h1 rook", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "h8h1", "Qq"},
+		{"queen takes h8 rook", "r3k2r/8/8/8/8/2Q5/8/R3K2R w KQkq - 0 1", "c3h8", "KQq"},
+		{"capture elsewhere keeps rights", "r3k2r/2p5/8/8/8/2Q5/8/R3K2R w KQkq - 0 1", "c3c7", "KQkq"},
+		{"promotion takes h8 rook", "r3k2r/6P1/8/8/8/8/8/R3K2R w KQkq - 0 1", "g7h8q", "KQq"},
+	}
+
+	for _, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if err := b.MakeUCIMove(test.move); err != nil {
+			t.Fatalf("%s: %s: %v", test.name, test.move, err)
+		}
+		if got := b.CastlingRights.String(); got != test.wantRights {
+			t.Errorf("%s: castling rights after %s = %q, want %q", test.name, test.move, got, test.wantRights)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package board
+
+// Color is the side a piece or player belongs to
+type Color bool
+
+const (
+	White Color = true
+	Black Color = false
+)
+
+// Opposite returns the other color
+func (c Color) Opposite() Color {
+	return !c
+}
+
+// String returns "white" or "black"
+func (c Color) String() string {
+	if c == White {
+		return "white"
+	}
+	return "black"
+}
+
+// PawnDirection returns the rank step of a pawn advance: -1 for White (rank index 0 is
+// the 8th rank), +1 for Black
+func (c Color) PawnDirection() int {
+	if c == White {
+		return -1
+	}
+	return 1
+}
+
+// ColorOf returns the color of a non-empty piece
+func ColorOf(piece int) Color {
+	return Color(piece < BP)
+}
+
+// colorIndex returns 0 for White and 1 for Black, for arrays indexed by color
+func colorIndex(c Color) int {
+	if c == White {
+		return 0
+	}
+	return 1
+}
+
+// SideToMove returns the color whose turn it is
+func (b *Board) SideToMove() Color {
+	return Color(b.WhiteToMove)
+}
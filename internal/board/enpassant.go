@@ -0,0 +1,61 @@
+package board
+
+import "github.com/zully/chess-engine/internal/moves"
+
+// GetEnPassantCaptures returns the legal en passant captures for the side to move: one for
+// each of its pawns beside the pawn that just advanced two squares, unless taking would
+// expose its own king (e.g. both pawns leaving a rank between the king and a rook)
+func (b *Board) GetEnPassantCaptures() []moves.Move {
+	var legal []moves.Move
+	for _, capture := range b.enPassantCaptures() {
+		if b.IsMoveLegal(capture.ToUCI()) {
+			legal = append(legal, capture)
+		}
+	}
+	return legal
+}
+
+// enPassantCaptures returns the en passant captures for the side to move, without checking
+// king safety
+func (b *Board) enPassantCaptures() []moves.Move {
+	if b.EnPassant == "" {
+		return nil
+	}
+
+	side := b.SideToMove()
+	targetRank, targetFile := GetSquareCoords(b.EnPassant)
+	pawnRank := targetRank - side.PawnDirection() // Rank of both the capturing and the captured pawn
+	ownPawn, enemyPawn := WP, BP
+	if side == Black {
+		ownPawn, enemyPawn = BP, WP
+	}
+	if pawnRank < 0 || pawnRank > 7 || b.GetPiece(pawnRank, targetFile) != enemyPawn {
+		return nil
+	}
+
+	var captures []moves.Move
+	for _, fromFile := range []int{targetFile - 1, targetFile + 1} {
+		if fromFile < 0 || fromFile > 7 || b.GetPiece(pawnRank, fromFile) != ownPawn {
+			continue
+		}
+		captures = append(captures, moves.Move{
+			From:      GetSquareName(pawnRank, fromFile),
+			To:        b.EnPassant,
+			Piece:     "P",
+			Capture:   true,
+			EnPassant: true,
+		})
+	}
+	return captures
+}
+
+// isEnPassantCapture reports whether moving from one square to the other is an en passant
+// capture by the side to move
+func (b *Board) isEnPassantCapture(from, to string) bool {
+	for _, capture := range b.enPassantCaptures() {
+		if capture.From == from && capture.To == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package board
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEnPassantAllCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		wantFrom []string // Capturing pawns, in file order
+	}{
+		{"no en passant square", "4k3/8/8/3Pp3/8/8/8/4K3 w - - 0 1", nil},
+		{"capture left", "4k3/8/8/4pP2/8/8/8/4K3 w - e6 0 1", []string{"f5"}},
+		{"capture right", "4k3/8/8/3Pp3/8/8/8/4K3 w - e6 0 1", []string{"d5"}},
+		{"two capturing pawns", "4k3/8/8/3PpP2/8/8/8/4K3 w - e6 0 1", []string{"d5", "f5"}},
+		{"black captures", "4k3/8/8/8/3pPp2/8/8/4K3 b - e3 0 1", []string{"d4", "f4"}},
+		{"edge file", "4k3/8/8/pP6/8/8/8/4K3 w - a6 0 1", []string{"b5"}},
+		{"horizontal pin", "8/8/8/K2Pp2r/8/8/8/7k w - e6 0 1", nil},
+		{"second pawn blocks the pin", "8/8/8/K2PpP1r/8/8/8/7k w - e6 0 1", []string{"d5", "f5"}},
+		{"after promotion", "4k3/8/8/8/3pP3/8/8/4K2Q b - e3 0 1", []string{"d4"}},
+	}
+
+	for _, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		var from []string
+		for _, capture := range b.GetEnPassantCaptures() {
+			if capture.To != b.EnPassant || capture.Piece != "P" || !capture.Capture || !capture.EnPassant {
+				t.Errorf("%s: malformed capture %+v", test.name, capture)
+			}
+			from = append(from, capture.From)
+		}
+		slices.Sort(from)
+		if !slices.Equal(from, test.wantFrom) {
+			t.Errorf("%s: captures from %v, want %v", test.name, from, test.wantFrom)
+			continue
+		}
+
+		// Every capture must be playable and remove the pawn that advanced
+		for _, capture := range b.GetEnPassantCaptures() {
+			next := b.Clone()
+			if err := next.MakeUCIMove(capture.ToUCI()); err != nil {
+				t.Errorf("%s: %s: %v", test.name, capture.ToUCI(), err)
+				continue
+			}
+			captured := capture.To[0:1] + capture.From[1:2]
+			if piece := next.GetPiece(GetSquareCoords(captured)); piece != Empty {
+				t.Errorf("%s: %s left piece %d on %s", test.name, capture.ToUCI(), piece, captured)
+			}
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package board
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
 
+// StartingFEN is the FEN of the standard starting position
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
 // ToFEN converts the current board position to FEN notation
 func (b *Board) ToFEN() string {
 	var fen strings.Builder
@@ -42,24 +46,7 @@ func (b *Board) ToFEN() string {
 
 	// 3. Castling availability
 	fen.WriteRune(' ')
-	castling := ""
-	if b.CastlingRights&1 != 0 { // White kingside
-		castling += "K"
-	}
-	if b.CastlingRights&2 != 0 { // White queenside
-		castling += "Q"
-	}
-	if b.CastlingRights&4 != 0 { // Black kingside
-		castling += "k"
-	}
-	if b.CastlingRights&8 != 0 { // Black queenside
-		castling += "q"
-	}
-	if castling == "" {
-		fen.WriteRune('-')
-	} else {
-		fen.WriteString(castling)
-	}
+	fen.WriteString(b.CastlingRights.String())
 
 	// 4. En passant target square
 	fen.WriteRune(' ')
@@ -80,6 +67,199 @@ func (b *Board) ToFEN() string {
 	return fen.String()
 }
 
+// FromFEN creates a board from a FEN string. Missing halfmove clock and fullmove
+// number fields default to "0 1", and castling rights that the king and rook
+// placement make impossible are dropped.
+func FromFEN(fen string) (*Board, error) {
+	fields := strings.Fields(fen)
+	if len(fields) < 4 || len(fields) > 6 {
+		return nil, fmt.Errorf("FEN must have 4 to 6 fields, got %d", len(fields))
+	}
+
+	b := &Board{
+		MovesPlayed:     make([]string, 0),
+		UCIMoves:        make([]string, 0),
+		PositionHistory: make(map[uint64]int),
+		FullMoveNumber:  1,
+
+		FiftyMoveAutomatic: true,
+	}
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			b.Squares[rank][file].Name = GetSquareName(rank, file)
+		}
+	}
+
+	// 1. Piece placement
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return nil, fmt.Errorf("FEN board must have 8 ranks, got %d", len(ranks))
+	}
+	for rank, row := range ranks {
+		file := 0
+		for _, c := range row {
+			if c >= '1' && c <= '8' {
+				file += int(c - '0')
+				continue
+			}
+			piece := fenCharToPiece(c)
+			if piece == Empty {
+				return nil, fmt.Errorf("invalid piece %q in FEN", c)
+			}
+			if file > 7 {
+				return nil, fmt.Errorf("FEN rank %d has more than 8 squares", 8-rank)
+			}
+			b.Squares[rank][file].Piece = piece
+			file++
+		}
+		if file != 8 {
+			return nil, fmt.Errorf("FEN rank %d does not have 8 squares", 8-rank)
+		}
+	}
+
+	// 2. Active color
+	switch fields[1] {
+	case "w":
+		b.WhiteToMove = true
+	case "b":
+		b.WhiteToMove = false
+	default:
+		return nil, fmt.Errorf("invalid active color %q", fields[1])
+	}
+
+	// 3. Castling availability
+	if fields[2] != "-" {
+		for _, c := range fields[2] {
+			switch c {
+			case 'K':
+				b.CastlingRights.WhiteKingside = true
+			case 'Q':
+				b.CastlingRights.WhiteQueenside = true
+			case 'k':
+				b.CastlingRights.BlackKingside = true
+			case 'q':
+				b.CastlingRights.BlackQueenside = true
+			default:
+				return nil, fmt.Errorf("invalid castling availability %q", fields[2])
+			}
+		}
+	}
+	b.CastlingRights = b.CastlingRights.intersect(b.possibleCastlingRights())
+
+	// 4. En passant target square
+	if fields[3] != "-" {
+		epRank, epFile := GetSquareCoords(fields[3])
+		if len(fields[3]) != 2 || epRank < 0 || epRank > 7 || epFile < 0 || epFile > 7 {
+			return nil, fmt.Errorf("invalid en passant square %q", fields[3])
+		}
+		// The target is behind a pawn that just advanced two squares
+		expectedRank, pawnRank, pawn := 2, 3, BP
+		if !b.WhiteToMove {
+			expectedRank, pawnRank, pawn = 5, 4, WP
+		}
+		if epRank != expectedRank || b.GetPiece(pawnRank, epFile) != pawn {
+			return nil, fmt.Errorf("en passant square %s does not match the position", fields[3])
+		}
+		b.EnPassant = fields[3]
+	}
+
+	// 5-6. Halfmove clock and fullmove number (optional)
+	if len(fields) > 4 {
+		halfMoves, err := strconv.Atoi(fields[4])
+		if err != nil || halfMoves < 0 {
+			return nil, fmt.Errorf("invalid halfmove clock %q", fields[4])
+		}
+		b.HalfMoveClock = halfMoves
+	}
+	if len(fields) > 5 {
+		fullMoves, err := strconv.Atoi(fields[5])
+		if err != nil || fullMoves < 0 {
+			return nil, fmt.Errorf("invalid fullmove number %q", fields[5])
+		}
+		if fullMoves > 0 {
+			b.FullMoveNumber = fullMoves
+		}
+	}
+
+	b.locateKings()
+	b.Hash = b.computeHash()
+	b.RecordPosition()
+
+	return b, nil
+}
+
+// SetupFromFEN replaces the position of an existing board with a FEN, in place, so references
+// to the board stay valid. The move, position and capture histories are cleared. On error the
+// board is left unchanged.
+func (b *Board) SetupFromFEN(fen string) error {
+	next, err := FromFEN(fen)
+	if err != nil {
+		return err
+	}
+	*b = *next
+	return nil
+}
+
+// Reset sets up the starting position in place, like SetupFromFEN(StartingFEN)
+func (b *Board) Reset() {
+	*b = *NewBoard()
+}
+
+// NormalizeFEN parses and validates a FEN and returns it in canonical form
+func NormalizeFEN(fen string) (string, error) {
+	b, err := FromFEN(fen)
+	if err != nil {
+		return "", err
+	}
+	if err := b.Validate(); err != nil {
+		return "", err
+	}
+	return b.ToFEN(), nil
+}
+
+// possibleCastlingRights returns the castling rights allowed by the king and rook placement
+func (b *Board) possibleCastlingRights() CastlingRights {
+	whiteKing, blackKing := b.GetPiece(7, 4) == WK, b.GetPiece(0, 4) == BK
+	return CastlingRights{
+		WhiteKingside:  whiteKing && b.GetPiece(7, 7) == WR,
+		WhiteQueenside: whiteKing && b.GetPiece(7, 0) == WR,
+		BlackKingside:  blackKing && b.GetPiece(0, 7) == BR,
+		BlackQueenside: blackKing && b.GetPiece(0, 0) == BR,
+	}
+}
+
+// fenCharToPiece converts a FEN character to its piece constant (Empty if invalid)
+func fenCharToPiece(c rune) int {
+	switch c {
+	case 'P':
+		return WP
+	case 'N':
+		return WN
+	case 'B':
+		return WB
+	case 'R':
+		return WR
+	case 'Q':
+		return WQ
+	case 'K':
+		return WK
+	case 'p':
+		return BP
+	case 'n':
+		return BN
+	case 'b':
+		return BB
+	case 'r':
+		return BR
+	case 'q':
+		return BQ
+	case 'k':
+		return BK
+	default:
+		return Empty
+	}
+}
+
 // pieceToFENChar converts a piece constant to its FEN character representation
 func pieceToFENChar(piece int) rune {
 	switch piece {
@@ -111,3 +291,11 @@ func pieceToFENChar(piece int) rune {
 		return '?'
 	}
 }
+
+// NullMoveFEN returns the FEN of the current position as if the side to move had passed:
+// the active color is flipped and the en passant target is cleared
+func (b *Board) NullMoveFEN() string {
+	next := b.Clone()
+	next.MakeNullMove()
+	return next.ToFEN()
+}
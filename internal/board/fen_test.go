@@ -0,0 +1,181 @@
+package board
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFENRoundTrip(t *testing.T) {
+	fens := []string{
+		// Starting position and Kiwipete
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+
+		// Castling right subsets
+		"r3k2r/8/8/8/8/8/8/R3K2R w K - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w Q - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R b k - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R b q - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w KQ - 3 10",
+		"r3k2r/8/8/8/8/8/8/R3K2R b kq - 5 12",
+		"r3k2r/8/8/8/8/8/8/R3K2R w Kk - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w Qq - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R w - - 0 1",
+
+		// En passant targets
+		"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3",
+		"rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2",
+		"rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 3",
+		"8/8/8/2k5/3Pp3/8/8/4K3 b - d3 0 1",
+
+		// Pawn endgames
+		"8/8/8/4k3/8/8/4P3/4K3 w - - 0 1",
+		"8/5k2/8/1p6/1P6/8/5K2/8 b - - 12 40",
+		"8/p7/1p3k2/8/8/1P3K2/P7/8 w - - 0 35",
+		"8/8/3k4/3p4/3P4/3K4/8/8 w - - 20 50",
+		"8/pp3k2/8/8/8/8/PP3K2/8 b - - 0 28",
+	}
+
+	for _, fen := range fens {
+		b, err := FromFEN(fen)
+		if err != nil {
+			t.Errorf("FromFEN(%q): %v", fen, err)
+			continue
+		}
+		if got := b.ToFEN(); got != fen {
+			t.Errorf("round trip of %q gave %q", fen, got)
+		}
+	}
+}
+
+func TestFENHalfMoveClock(t *testing.T) {
+	b, err := FromFEN("4k1n1/8/8/8/8/8/8/4K1N1 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Quiet knight and king moves only: no capture or pawn move resets the clock
+	cycle := []string{"g1f3", "g8f6", "f3g1", "f6g8", "e1d1", "e8d8", "d1e1", "d8e8"}
+	for ply := 0; ply < 60; ply++ {
+		if err := b.MakeUCIMove(cycle[ply%len(cycle)]); err != nil {
+			t.Fatalf("ply %d (%s): %v", ply, cycle[ply%len(cycle)], err)
+		}
+	}
+
+	if b.HalfMoveClock != 60 {
+		t.Errorf("HalfMoveClock = %d, want 60", b.HalfMoveClock)
+	}
+	if fields := strings.Fields(b.ToFEN()); fields[4] != "60" {
+		t.Errorf("FEN half-move field = %q, want \"60\"", fields[4])
+	}
+}
+
+func FuzzFromFEN(f *testing.F) {
+	seeds := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq -",
+		"8/8/8/2k5/3Pp3/8/8/4K3 b - d3 0 1",
+		"", "8/8/8/8/8/8/8/8 w - - 0 1", "9/8/8/8/8/8/8/8 w - -", "k7/8/8/8/8/8/8/7K w - z9 x y",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, fen string) {
+		b, err := FromFEN(fen)
+		if err != nil {
+			return
+		}
+		out := b.ToFEN()
+		again, err := FromFEN(out)
+		if err != nil {
+			t.Fatalf("FromFEN(%q) produced %q, which does not parse: %v", fen, out, err)
+		}
+		if got := again.ToFEN(); got != out {
+			t.Errorf("FEN %q is not stable: %q then %q", fen, out, got)
+		}
+	})
+}
+
+func TestNullMoveFEN(t *testing.T) {
+	// Black to move after 3.Qh5: passing lets White show its threat, Qxf7#
+	b, err := FromFEN("r1bqkbnr/pppp1ppp/2n5/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR b KQkq - 3 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "r1bqkbnr/pppp1ppp/2n5/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - 3 3"
+	if got := b.NullMoveFEN(); got != want {
+		t.Errorf("NullMoveFEN() = %q, want %q", got, want)
+	}
+
+	threat, err := FromFEN(b.NullMoveFEN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := threat.MakeUCIMove("h5f7"); err != nil {
+		t.Fatal(err)
+	}
+	if !threat.IsCheckmate(Black) {
+		t.Error("Qxf7 from the null-move position is not mate")
+	}
+
+	// The en passant square is cleared, as the capture is only possible right after the pawn moved
+	b, err = FromFEN("rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.NullMoveFEN(), "rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3"; got != want {
+		t.Errorf("NullMoveFEN() = %q, want %q", got, want)
+	}
+}
+
+func TestFENRejectsInvalidPositions(t *testing.T) {
+	tests := []struct {
+		name        string
+		fen         string
+		validateErr bool // FromFEN accepts the FEN and Validate rejects the position
+	}{
+		// Truncated FENs
+		{"empty", "", false},
+		{"placement only", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR", false},
+		{"no castling field", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w", false},
+		{"no en passant field", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq", false},
+		{"seven ranks", "rnbqkbnr/pppppppp/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", false},
+		{"short rank", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBN w KQkq - 0 1", false},
+
+		// Boards that are too big
+		{"nine ranks", "rnbqkbnr/pppppppp/8/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", false},
+		{"nine files", "rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", false},
+		{"long rank", "rnbqkbnrr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", false},
+		{"too many fields", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1 1", false},
+
+		// The side not to move is in check
+		{"white in check, black to move", "4k3/8/8/8/8/8/4r3/4K3 b - - 0 1", true},
+		{"black in check, white to move", "4k3/8/8/8/8/8/4Q3/4K3 w - - 0 1", true},
+		{"black king attacked by a knight, white to move", "4k3/8/3N4/8/8/8/8/4K3 w - - 0 1", true},
+		{"kings next to each other", "8/8/8/3kK3/8/8/8/8 w - - 0 1", true},
+
+		// Other impossible positions
+		{"no black king", "8/8/8/8/8/8/8/4K3 w - - 0 1", true},
+		{"two white kings", "4k3/8/8/8/8/8/8/3KK3 w - - 0 1", true},
+		{"pawn on the back rank", "4k2P/8/8/8/8/8/8/4K3 w - - 0 1", true},
+	}
+
+	for _, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err == nil {
+			err = b.Validate()
+			if err != nil && !test.validateErr {
+				t.Errorf("%s: FromFEN(%q) accepted the FEN; Validate: %v", test.name, test.fen, err)
+			}
+		} else if test.validateErr {
+			t.Errorf("%s: FromFEN(%q): %v, want the parse to succeed", test.name, test.fen, err)
+		}
+		if err == nil {
+			t.Errorf("%s: %q was accepted", test.name, test.fen)
+		}
+		if _, err := NormalizeFEN(test.fen); err == nil {
+			t.Errorf("%s: NormalizeFEN(%q) accepted it", test.name, test.fen)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package board
+
+// promotionPieces are the UCI suffixes for the pieces a pawn can promote to
+var promotionPieces = []string{"q", "r", "b", "n"}
+
+// GetLegalMoves returns every legal move for the side to move in UCI notation
+func (b *Board) GetLegalMoves() []string {
+	var legalMoves []string
+
+	// Out of check, a move by a piece other than the king can only expose the king by
+	// leaving a pin ray, so only king moves, pinned pieces and en passant captures (which
+	// can open a line through two pawns) need a full king safety test.
+	side := b.SideToMove()
+	inCheck := b.IsInCheck(side)
+	kingRank, kingFile := b.findKing(side)
+	pins := make(map[[2]int]PinInfo)
+	for _, pin := range b.GetPinnedPieces(side) {
+		pins[[2]int{pin.PinnedRank, pin.PinnedFile}] = pin
+	}
+
+	for _, loc := range b.GetAllPieces(side) {
+		fromSquare := GetSquareName(loc.Rank, loc.File)
+		pin, pinned := pins[[2]int{loc.Rank, loc.File}]
+		isKing := loc.Piece == WK || loc.Piece == BK
+
+		for toRank := 0; toRank < 8; toRank++ {
+			for toFile := 0; toFile < 8; toFile++ {
+				if !b.canReach(loc.Piece, loc.Rank, loc.File, toRank, toFile) {
+					continue
+				}
+
+				uciMove := fromSquare + GetSquareName(toRank, toFile)
+				enPassant := (loc.Piece == WP || loc.Piece == BP) && toFile != loc.File && b.isEnPassantCapture(fromSquare, GetSquareName(toRank, toFile))
+				needsTest := inCheck || isKing || enPassant
+				if !needsTest && pinned && !pin.alongPin(kingRank, kingFile, toRank, toFile) {
+					continue
+				}
+
+				// Pawns reaching the last rank must promote
+				if (loc.Piece == WP && toRank == 0) || (loc.Piece == BP && toRank == 7) {
+					for _, promotion := range promotionPieces {
+						if !needsTest || b.IsMoveLegal(uciMove+promotion) {
+							legalMoves = append(legalMoves, uciMove+promotion)
+						}
+					}
+					continue
+				}
+
+				if !needsTest || b.IsMoveLegal(uciMove) {
+					legalMoves = append(legalMoves, uciMove)
+				}
+			}
+		}
+	}
+
+	// Castling
+	rank := "1"
+	if !b.WhiteToMove {
+		rank = "8"
+	}
+	if b.canCastle("O-O", b.SideToMove()) {
+		legalMoves = append(legalMoves, "e"+rank+"g"+rank)
+	}
+	if b.canCastle("O-O-O", b.SideToMove()) {
+		legalMoves = append(legalMoves, "e"+rank+"c"+rank)
+	}
+
+	return legalMoves
+}
+
+// canReach reports whether the piece's movement rules allow it to reach the target square,
+// ignoring king safety (castling is handled separately)
+func (b *Board) canReach(piece, fromRank, fromFile, toRank, toFile int) bool {
+	if fromRank == toRank && fromFile == toFile {
+		return false
+	}
+
+	// Can't capture own pieces
+	target := b.GetPiece(toRank, toFile)
+	if target != Empty && (target < BP) == (piece < BP) {
+		return false
+	}
+
+	isCapture := target != Empty
+	if (piece == WP || piece == BP) && fromFile != toFile && !isCapture {
+		isCapture = b.isEnPassantCapture(GetSquareName(fromRank, fromFile), GetSquareName(toRank, toFile))
+	}
+	return b.isValidMove(piece, fromRank, fromFile, toRank, toFile, isCapture)
+}
+
+// IsMoveLegal reports whether MakeUCIMove would accept the move, without changing the board:
+// the format, the piece's movement rules and king safety are checked on a copy
+func (b *Board) IsMoveLegal(uciMove string) bool {
+	return b.Clone().makeUCIMove(uciMove) == nil
+}
+
+// Perft counts the leaf nodes of the legal move tree to the given depth, for checking
+// move generation against known totals (20, 400, 8902, ... from the starting position)
+func (b *Board) Perft(depth int) int64 {
+	if depth <= 0 {
+		return 1
+	}
+
+	legalMoves := b.GetLegalMoves()
+	if depth == 1 {
+		return int64(len(legalMoves))
+	}
+
+	var nodes int64
+	for _, move := range legalMoves {
+		next := b.Clone()
+		if err := next.makeUCIMove(move); err != nil {
+			continue
+		}
+		nodes += next.Perft(depth - 1)
+	}
+	return nodes
+}
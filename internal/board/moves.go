@@ -19,18 +19,18 @@ func (b *Board) FindPieceForMove(move *moves.Move) (string, error) {
 	}
 
 	// Handle castling
-	if move.Castle != "" {
+	if move.IsCastle() {
 		// Castle moves are fully specified, so just return the from square
 		return move.From, nil
 	}
 
 	pieceType := move.Piece
-	isWhite := b.WhiteToMove
+	color := b.SideToMove()
 
 	// Special handling for pawns - find the actual starting square
 	if pieceType == "P" {
 		fromFile := targetFile
-		var fromRank int
+		fromRank := targetRank - color.PawnDirection() // One square behind the target
 
 		// Process the source file for captures
 		if len(move.From) > 0 {
@@ -42,19 +42,12 @@ func (b *Board) FindPieceForMove(move *moves.Move) (string, error) {
 
 		// Expected piece type
 		expectedPiece := WP
-		if !isWhite {
+		if color == Black {
 			expectedPiece = BP
 		}
 
 		// For captures, look one square diagonally back
 		if move.Capture {
-			// Get the rank for the capturing pawn
-			if !isWhite {
-				fromRank = targetRank - 1 // Black capturing pawn must be one rank higher
-			} else {
-				fromRank = targetRank + 1 // White capturing pawn must be one rank lower
-			}
-
 			if fromFile < 0 || fromFile > 7 || fromRank < 0 || fromRank > 7 {
 				return "", fmt.Errorf("invalid source square: rank %d, file %d out of bounds", fromRank, fromFile)
 			}
@@ -65,12 +58,6 @@ func (b *Board) FindPieceForMove(move *moves.Move) (string, error) {
 			}
 		} else {
 			// For normal pawn moves, try one square back first
-			if isWhite {
-				fromRank = targetRank + 1
-			} else {
-				fromRank = targetRank - 1
-			}
-
 			if fromRank >= 0 && fromRank <= 7 {
 				piece := b.GetPiece(fromRank, fromFile)
 				if piece == expectedPiece && canPawnMove(b, fromRank, fromFile, targetRank, targetFile, false) {
@@ -79,9 +66,9 @@ func (b *Board) FindPieceForMove(move *moves.Move) (string, error) {
 			}
 
 			// Then try two squares back from the starting position
-			if isWhite && targetRank == 4 {
+			if color == White && targetRank == 4 {
 				fromRank = 6 // White pawn on rank 2 (index 6)
-			} else if !isWhite && targetRank == 3 {
+			} else if color == Black && targetRank == 3 {
 				fromRank = 1 // Black pawn on rank 7 (index 1)
 			}
 
@@ -100,37 +87,37 @@ func (b *Board) FindPieceForMove(move *moves.Move) (string, error) {
 	// Convert piece letter to piece constant
 	switch pieceType {
 	case "P":
-		if isWhite {
+		if color == White {
 			piece = WP
 		} else {
 			piece = BP
 		}
 	case "N":
-		if isWhite {
+		if color == White {
 			piece = WN
 		} else {
 			piece = BN
 		}
 	case "B":
-		if isWhite {
+		if color == White {
 			piece = WB
 		} else {
 			piece = BB
 		}
 	case "R":
-		if isWhite {
+		if color == White {
 			piece = WR
 		} else {
 			piece = BR
 		}
 	case "Q":
-		if isWhite {
+		if color == White {
 			piece = WQ
 		} else {
 			piece = BQ
 		}
 	case "K":
-		if isWhite {
+		if color == White {
 			piece = WK
 		} else {
 			piece = BK
@@ -206,6 +193,9 @@ func (b *Board) FindPieceForMove(move *moves.Move) (string, error) {
 					} else if move.Capture {
 						continue // Move was marked as a capture but square is empty
 					}
+					if b.leavesKingInCheck(rank, file, targetRank, targetFile) {
+						continue // Pinned pieces don't count, which is why SAN leaves them out
+					}
 					return GetSquareName(rank, file), nil
 				}
 			}
@@ -216,7 +206,19 @@ func (b *Board) FindPieceForMove(move *moves.Move) (string, error) {
 }
 
 // MakeMove makes a move on the board using algebraic notation
-func (b *Board) MakeMove(notation string) error {
+func (b *Board) MakeMove(notation string) (err error) {
+	// Illegal moves leave the board untouched: the position is put back if the move fails
+	saved := b.savePosition()
+	defer func() {
+		if err != nil {
+			b.restorePosition(saved)
+		}
+	}()
+	return b.makeMove(notation)
+}
+
+// makeMove applies an algebraic move with all of its side effects
+func (b *Board) makeMove(notation string) error {
 	move, err := moves.ParseAlgebraic(notation, b.WhiteToMove)
 	if err != nil {
 		return err
@@ -249,7 +251,7 @@ func (b *Board) MakeMove(notation string) error {
 	toSquare := b.GetSquare(move.To)
 
 	// Skip square validation for castling moves (they're handled specially)
-	if move.Castle == "" {
+	if !move.IsCastle() {
 		if fromSquare == nil || toSquare == nil {
 			if fromSquare == nil {
 				return fmt.Errorf("invalid from square: %s", move.From)
@@ -273,24 +275,8 @@ func (b *Board) MakeMove(notation string) error {
 	piece := fromSquare.Piece
 	isValid := false
 
-	// First check if the move would get us out of check
-	if b.IsInCheck(b.WhiteToMove) {
-		// Try the move temporarily
-		oldFromPiece := fromSquare.Piece
-		oldToPiece := toSquare.Piece
-		toSquare.Piece = fromSquare.Piece
-		fromSquare.Piece = Empty
-
-		stillInCheck := b.IsInCheck(b.WhiteToMove)
-
-		// Undo the temporary move
-		fromSquare.Piece = oldFromPiece
-		toSquare.Piece = oldToPiece
-
-		if stillInCheck {
-			return fmt.Errorf("must respond to check")
-		}
-	}
+	mover := b.SideToMove()
+	wasInCheck := b.IsInCheck(mover)
 
 	switch piece {
 	case WP, BP:
@@ -305,8 +291,8 @@ func (b *Board) MakeMove(notation string) error {
 		isValid = CanQueenMove(b, startRank, startFile, endRank, endFile)
 	case WK, BK:
 		// Handle castling moves specially
-		if move.Castle != "" {
-			isValid = b.canCastle(move.Castle, b.WhiteToMove)
+		if move.IsCastle() {
+			isValid = b.canCastle(move.Castle, b.SideToMove())
 		} else {
 			isValid = canKingMove(startRank, startFile, endRank, endFile)
 		}
@@ -318,34 +304,47 @@ func (b *Board) MakeMove(notation string) error {
 		return fmt.Errorf("illegal move for %s: %s", move.Piece, notation)
 	}
 
+	// Record canonical SAN generated from the board rather than the notation as typed,
+	// so the history always has correct disambiguation and check marks
+	if move.IsCastle() {
+		notation = move.Castle
+	} else {
+		notation = b.uciToAlgebraic(move.ToUCI())
+	}
+
+	// Pawn moves and captures reset the fifty-move clock (en passant captures are pawn moves)
+	isPawnMove := piece == WP || piece == BP
+	isCaptureMove := toSquare != nil && toSquare.Piece != Empty
+	isEnPassantCapture := !move.IsCastle() && b.isEnPassantCapture(move.From, move.To)
+	capturedPiece, capturedSquare := Empty, move.To
+	if isCaptureMove {
+		capturedPiece = toSquare.Piece
+	}
+
 	// Clear en passant target from previous move
-	b.EnPassant = ""
+	b.setEnPassant("")
+
+	// Update castling rights if king or rook moves, or a rook is captured
+	b.updateCastlingRights(move.From, fromSquare.Piece, move.To, capturedPiece)
 
-	// Update castling rights if king or rook moves
-	b.updateCastlingRights(move.From, fromSquare.Piece)
+	// Track the move in UCI notation alongside the algebraic history
+	uciMove := move.From + move.To
 
 	// Handle castling moves specially
-	if move.Castle != "" {
-		b.executeCastling(move.Castle, b.WhiteToMove)
+	if move.IsCastle() {
+		b.executeCastling(move.Castle, b.SideToMove())
+		uciMove = move.ToUCI()
 	} else {
-		// Check for en passant capture before making the move
-		isEnPassantCapture := move.EnPassant || (move.Piece == "P" && move.Capture && toSquare.Piece == Empty)
-
 		// Make the move
-		toSquare.Piece = fromSquare.Piece
-		fromSquare.Piece = Empty
+		b.setPiece(endRank, endFile, fromSquare.Piece)
+		b.setPiece(startRank, startFile, Empty)
 
 		// Handle en passant capture - remove the captured pawn
 		if isEnPassantCapture {
-			capturedPawnRank := endRank
-			if b.WhiteToMove {
-				capturedPawnRank = endRank + 1 // White captures black pawn one rank below
-			} else {
-				capturedPawnRank = endRank - 1 // Black captures white pawn one rank above
-			}
-			capturedPawnSquare := b.GetSquareByCoords(capturedPawnRank, endFile)
-			if capturedPawnSquare != nil {
-				capturedPawnSquare.Piece = Empty
+			capturedPawnRank := endRank - b.SideToMove().PawnDirection() // Just behind the target square
+			if capturedPawnRank >= 0 && capturedPawnRank <= 7 {
+				capturedPiece, capturedSquare = b.GetPiece(capturedPawnRank, endFile), GetSquareName(capturedPawnRank, endFile)
+				b.setPiece(capturedPawnRank, endFile, Empty)
 			}
 		}
 
@@ -353,7 +352,7 @@ func (b *Board) MakeMove(notation string) error {
 		if move.Piece == "P" && abs(endRank-startRank) == 2 {
 			// Set en passant target square (the square the pawn passed over)
 			targetRank := (startRank + endRank) / 2
-			b.EnPassant = GetSquareName(targetRank, endFile)
+			b.setEnPassant(GetSquareName(targetRank, endFile))
 		}
 
 		// Check for pawn promotion
@@ -366,92 +365,110 @@ func (b *Board) MakeMove(notation string) error {
 
 			// Apply the promotion
 			isWhitePiece := toSquare.Piece == WP
+			var promoted int
 			switch promotionPiece {
 			case "Q":
 				if isWhitePiece {
-					toSquare.Piece = WQ
+					promoted = WQ
 				} else {
-					toSquare.Piece = BQ
+					promoted = BQ
 				}
 			case "R":
 				if isWhitePiece {
-					toSquare.Piece = WR
+					promoted = WR
 				} else {
-					toSquare.Piece = BR
+					promoted = BR
 				}
 			case "B":
 				if isWhitePiece {
-					toSquare.Piece = WB
+					promoted = WB
 				} else {
-					toSquare.Piece = BB
+					promoted = BB
 				}
 			case "N":
 				if isWhitePiece {
-					toSquare.Piece = WN
+					promoted = WN
 				} else {
-					toSquare.Piece = BN
+					promoted = BN
 				}
 			default:
 				// Fallback to Queen for invalid promotion pieces
 				if isWhitePiece {
-					toSquare.Piece = WQ
+					promoted = WQ
 				} else {
-					toSquare.Piece = BQ
+					promoted = BQ
 				}
 				promotionPiece = "Q"
 			}
+			b.setPiece(endRank, endFile, promoted)
 			// Add promotion notation only if not already present
 			if !strings.Contains(notation, "=") {
 				notation += "=" + promotionPiece
 			}
+			uciMove += strings.ToLower(promotionPiece)
 
 		}
 	}
 
-	// Switch turns
-	b.WhiteToMove = !b.WhiteToMove
+	// With every piece in place (including a pawn removed en passant), the king must be safe
+	if b.IsInCheck(mover) {
+		return kingSafetyError(wasInCheck)
+	}
 
-	// Record the position for repetition detection
-	b.RecordPosition()
+	if capturedPiece != Empty {
+		b.recordCapture(capturedPiece, piece, capturedSquare)
+	}
 
-	// Check for draw conditions (game state will handle display)
-	b.IsDraw() // Called for any side effects, web UI handles messaging
+	// Update the fifty-move clock and move number
+	b.updateMoveClocks(isPawnMove, isCaptureMove)
 
-	// Check if the opponent is in check after this move
-	if b.IsInCheck(b.WhiteToMove) {
-		// Check if it's checkmate
-		if b.IsCheckmate(b.WhiteToMove) {
-			// Add checkmate notation only if not already present
-			if !strings.Contains(notation, "#") && !strings.Contains(notation, "+") {
-				notation += "#"
-			}
+	// Switch turns
+	b.switchSide()
 
-		} else {
-			// Add check notation only if not already present
-			if !strings.Contains(notation, "+") && !strings.Contains(notation, "#") {
-				notation += "+"
-			}
+	// Record the position for repetition detection
+	b.RecordPosition()
 
-		}
-	}
+	// Add check or checkmate notation if the opponent is in check after this move
+	notation += b.checkSuffix()
 
 	// Record the move (with check notation if applicable)
 	b.MovesPlayed = append(b.MovesPlayed, notation)
+	b.UCIMoves = append(b.UCIMoves, uciMove)
 
 	return nil
 }
 
-// canCastle checks if the specified castling move is legal
-func (b *Board) canCastle(castleType string, isWhite bool) bool {
+// CanCastle reports whether the side to move can castle now; side is "O-O" or "O-O-O"
+func (b *Board) CanCastle(side string) bool {
+	return b.canCastle(side, b.SideToMove())
+}
+
+// CanCastleKingside reports whether the color could castle kingside in this position
+func (b *Board) CanCastleKingside(color Color) bool {
+	return b.canCastle("O-O", color)
+}
+
+// CanCastleQueenside reports whether the color could castle queenside in this position
+func (b *Board) CanCastleQueenside(color Color) bool {
+	return b.canCastle("O-O-O", color)
+}
+
+// canCastle checks if the specified castling move ("O-O" or "O-O-O") is legal for the color
+func (b *Board) canCastle(castleType string, color Color) bool {
+	// The side must still have the right to castle on this wing
+	if !b.hasCastlingRights(castleType, color) {
+		return false
+	}
+
 	// Check if king is currently in check (can't castle out of check)
-	if b.IsInCheck(isWhite) {
+	if b.IsInCheck(color) {
 		return false
 	}
 
 	var kingRank, rookRank int
 	var kingFromFile, kingToFile, rookFromFile int
 
-	if isWhite {
+	if color == White {
 		kingRank = 7 // White king on rank 1 (index 7)
 		rookRank = 7 // White rooks on rank 1 (index 7)
 	} else {
@@ -477,7 +494,7 @@ func (b *Board) canCastle(castleType string, isWhite bool) bool {
 	// Check that king and rook are in correct positions
 	expectedKing := WK
 	expectedRook := WR
-	if !isWhite {
+	if color == Black {
 		expectedKing = BK
 		expectedRook = BR
 	}
@@ -511,13 +528,13 @@ func (b *Board) canCastle(castleType string, isWhite bool) bool {
 	}
 
 	for file := minFile; file <= maxFile; file++ {
-		if b.IsSquareAttacked(kingRank, file, !isWhite) {
+		if b.IsSquareAttacked(kingRank, file, color.Opposite()) {
 			return false
 		}
 	}
 
 	// Check castling rights
-	if !b.hasCastlingRights(castleType, isWhite) {
+	if !b.hasCastlingRights(castleType, color) {
 		return false
 	}
 
@@ -525,60 +542,32 @@ func (b *Board) canCastle(castleType string, isWhite bool) bool {
 }
 
 // hasCastlingRights checks if the player still has the specified castling rights
-func (b *Board) hasCastlingRights(castleType string, isWhite bool) bool {
-	// Castling rights are stored as bits: 0001=WK, 0010=WQ, 0100=BK, 1000=BQ
-	if isWhite {
-		if castleType == "O-O" {
-			return (b.CastlingRights & 1) != 0 // White kingside
-		} else if castleType == "O-O-O" {
-			return (b.CastlingRights & 2) != 0 // White queenside
-		}
-	} else {
-		if castleType == "O-O" {
-			return (b.CastlingRights & 4) != 0 // Black kingside
-		} else if castleType == "O-O-O" {
-			return (b.CastlingRights & 8) != 0 // Black queenside
-		}
+func (b *Board) hasCastlingRights(castleType string, color Color) bool {
+	switch castleType {
+	case "O-O":
+		return b.CastlingRights.Kingside(color)
+	case "O-O-O":
+		return b.CastlingRights.Queenside(color)
 	}
 	return false
 }
 
-// updateCastlingRights removes castling rights when kings or rooks move
-func (b *Board) updateCastlingRights(fromSquare string, piece int) {
-	switch fromSquare {
-	case "e1": // White king
-		if piece == WK {
-			b.CastlingRights &^= 3 // Remove both white castling rights (bits 0 and 1)
-		}
-	case "a1": // White queenside rook
-		if piece == WR {
-			b.CastlingRights &^= 2 // Remove white queenside (bit 1)
-		}
-	case "h1": // White kingside rook
-		if piece == WR {
-			b.CastlingRights &^= 1 // Remove white kingside (bit 0)
-		}
-	case "e8": // Black king
-		if piece == BK {
-			b.CastlingRights &^= 12 // Remove both black castling rights (bits 2 and 3)
-		}
-	case "a8": // Black queenside rook
-		if piece == BR {
-			b.CastlingRights &^= 8 // Remove black queenside (bit 3)
-		}
-	case "h8": // Black kingside rook
-		if piece == BR {
-			b.CastlingRights &^= 4 // Remove black kingside (bit 2)
-		}
-	}
+// updateCastlingRights removes the castling rights a move gives up: those of a king or rook
+// leaving its home square (fromSquare, piece) and of a rook captured on its home square
+// (toSquare, captured). Pass Empty as captured when nothing was taken.
+func (b *Board) updateCastlingRights(fromSquare string, piece int, toSquare string, captured int) {
+	rights := b.CastlingRights
+	rights.removeForHomeSquare(fromSquare, piece)
+	rights.removeForHomeSquare(toSquare, captured)
+	b.setCastlingRights(rights)
 }
 
 // executeCastling performs the castling move (moves both king and rook)
-func (b *Board) executeCastling(castleType string, isWhite bool) {
+func (b *Board) executeCastling(castleType string, color Color) {
 	var kingRank, rookRank int
 	var kingFromFile, kingToFile, rookFromFile, rookToFile int
 
-	if isWhite {
+	if color == White {
 		kingRank = 7 // White king on rank 1 (index 7)
 		rookRank = 7 // White rooks on rank 1 (index 7)
 	} else {
@@ -603,11 +592,14 @@ func (b *Board) executeCastling(castleType string, isWhite bool) {
 
 	// Move the king
 	kingPiece := b.GetPiece(kingRank, kingFromFile)
-	b.Squares[kingRank][kingFromFile].Piece = Empty
-	b.Squares[kingRank][kingToFile].Piece = kingPiece
+	b.setPiece(kingRank, kingFromFile, Empty)
+	b.setPiece(kingRank, kingToFile, kingPiece)
 
 	// Move the rook
 	rookPiece := b.GetPiece(rookRank, rookFromFile)
-	b.Squares[rookRank][rookFromFile].Piece = Empty
-	b.Squares[rookRank][rookToFile].Piece = rookPiece
+	b.setPiece(rookRank, rookFromFile, Empty)
+	b.setPiece(rookRank, rookToFile, rookPiece)
+
+	// Castling uses up both of the side's castling rights
+	b.updateCastlingRights(GetSquareName(kingRank, kingFromFile), kingPiece, "", Empty)
 }
@@ -0,0 +1,139 @@
+package board
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestMakeMoveRejectsPinnedEnPassant(t *testing.T) {
+	// Both pawns leave the fifth rank, so dxe6 would expose the a5 king to the h5 rook
+	const fen = "8/8/8/K2Pp2r/8/8/8/7k w - e6 0 1"
+
+	for _, tc := range []struct {
+		name string
+		move func(b *Board) error
+	}{
+		{"UCI", func(b *Board) error { return b.MakeUCIMove("d5e6") }},
+		{"algebraic", func(b *Board) error { return b.MakeMove("dxe6") }},
+	} {
+		b, err := FromFEN(fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.move(b); err == nil {
+			t.Errorf("%s: en passant exposing the king was played", tc.name)
+		}
+		if got := b.ToFEN(); got != fen {
+			t.Errorf("%s: board changed to %q", tc.name, got)
+		}
+		if slices.Contains(b.GetLegalMoves(), "d5e6") {
+			t.Errorf("%s: d5e6 listed as a legal move", tc.name)
+		}
+	}
+
+	// Without the rook the same capture is legal and removes the e5 pawn
+	b, err := FromFEN("8/8/8/K2Pp3/8/8/8/7k w - e6 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.MakeMove("dxe6"); err != nil {
+		t.Fatalf("unpinned en passant: %v", err)
+	}
+	if got, want := b.ToFEN(), "8/8/4P3/K7/8/8/8/7k b - - 0 1"; got != want {
+		t.Errorf("after unpinned en passant: %q, want %q", got, want)
+	}
+}
+
+func TestMakeMoveRejectsDiscoveredCheck(t *testing.T) {
+	// The e2 knight is pinned against the e1 king by the e8 rook
+	const fen = "4r2k/8/8/8/8/8/4N3/4K3 w - - 0 1"
+	b, err := FromFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, move := range []string{"e2c3", "e2g3", "e2f4"} {
+		if err := b.MakeUCIMove(move); err == nil {
+			t.Errorf("pinned knight move %s was played", move)
+		}
+	}
+	if err := b.MakeMove("Nc3"); err == nil {
+		t.Error("pinned knight move Nc3 was played")
+	}
+	if got := b.ToFEN(); got != fen {
+		t.Errorf("board changed to %q", got)
+	}
+}
+
+func TestMakeMoveAtomicity(t *testing.T) {
+	const fen = "rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3"
+	b, err := FromFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := FromFEN(fen) // An identical board to compare every field against
+
+	invalid := []string{
+		"e5e7",  // Pawn can't move two squares from e5
+		"e7e6",  // Black piece
+		"e1g1",  // Castling through pieces
+		"d1d3",  // Queen blocked by the d2 pawn
+		"e5d6q", // Promotion suffix off the last rank
+		"e5f6n", // En passant capture with a promotion suffix
+		"g1g3",  // Not a knight move
+		"a2a5",  // Pawn three squares
+		"e9e4",  // Off the board
+		"e5e5",  // Null move
+	}
+	for _, move := range invalid {
+		if err := b.MakeUCIMove(move); err == nil {
+			t.Fatalf("invalid move %s was played", move)
+		}
+		if got := b.ToFEN(); got != fen {
+			t.Fatalf("after rejected %s the FEN is %q, want %q", move, got, fen)
+		}
+		if !reflect.DeepEqual(b, before) {
+			t.Fatalf("after rejected %s the board differs from before", move)
+		}
+	}
+
+	// The board is still usable: en passant is still available
+	if err := b.MakeUCIMove("e5f6"); err != nil {
+		t.Errorf("e5f6 en passant after the rejected moves: %v", err)
+	}
+}
+
+func TestMakeMoveAtomicityAfterPartialMove(t *testing.T) {
+	// These moves are only found illegal once the pieces are moved, and the king moves
+	// also clear the castling rights and the en passant target first
+	const fen = "4kr2/8/8/3pP3/4r3/8/4N3/4K2R w K d6 0 2"
+	before, _ := FromFEN(fen)
+
+	tests := []struct {
+		move string
+		play func(b *Board, move string) error
+	}{
+		{"e2c3", (*Board).MakeUCIMove}, // Pinned knight
+		{"e1f1", (*Board).MakeUCIMove}, // Into the f8 rook's file
+		{"Nc3", (*Board).MakeMove},
+		{"Kf1", (*Board).MakeMove},
+		{"Kf2", (*Board).MakeMove},
+	}
+	for _, tt := range tests {
+		b, err := FromFEN(fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tt.play(b, tt.move); err == nil {
+			t.Errorf("illegal move %s was played", tt.move)
+			continue
+		}
+		if !reflect.DeepEqual(b, before) {
+			t.Errorf("after rejected %s the board is %q, want %q", tt.move, b.ToFEN(), fen)
+		}
+		if err := b.MakeUCIMove("e5d6"); err != nil {
+			t.Errorf("e5d6 en passant after rejected %s: %v", tt.move, err)
+		}
+	}
+}
@@ -5,13 +5,12 @@ package board
 // canPawnMove checks if a pawn can make the given move
 func canPawnMove(b *Board, fromRank, fromFile, toRank, toFile int, isCapture bool) bool {
 	piece := b.GetPiece(fromRank, fromFile)
-	isWhite := piece < BP
+	color := ColorOf(piece)
 
-	// Get direction - white moves up the board (-1 in rank), black moves down (+1 in rank)
-	direction := -1              // White moves up the board (rank decreases)
+	// White moves up the board (rank index decreases), black moves down
+	direction := color.PawnDirection()
 	isStartRank := fromRank == 6 // White pawns start on rank 2 (index 6)
-	if !isWhite {
-		direction = 1               // Black moves down the board (rank increases)
+	if color == Black {
 		isStartRank = fromRank == 1 // Black pawns start on rank 7 (index 1)
 	}
 
@@ -29,24 +28,18 @@ func canPawnMove(b *Board, fromRank, fromFile, toRank, toFile int, isCapture boo
 		targetPiece := b.GetPiece(toRank, toFile)
 
 		// Check for normal capture
-		isTargetEnemy := (isWhite && targetPiece >= BP) || (!isWhite && targetPiece < BP)
-		if targetPiece != Empty && isTargetEnemy {
+		if targetPiece != Empty && ColorOf(targetPiece) != color {
 			return true
 		}
 
 		// Check for en passant capture
 		targetSquareName := GetSquareName(toRank, toFile)
 		if b.EnPassant == targetSquareName {
-			// Verify there's an enemy pawn to capture
-			capturedPawnRank := toRank
-			if isWhite {
-				capturedPawnRank = toRank + 1 // White captures black pawn one rank below
-			} else {
-				capturedPawnRank = toRank - 1 // Black captures white pawn one rank above
-			}
+			// Verify there's an enemy pawn to capture, just behind the target square
+			capturedPawnRank := toRank - direction
 			capturedPiece := b.GetPiece(capturedPawnRank, toFile)
 			expectedEnemyPawn := BP
-			if !isWhite {
+			if color == Black {
 				expectedEnemyPawn = WP
 			}
 			return capturedPiece == expectedEnemyPawn
@@ -150,10 +143,31 @@ func canKingMove(startRank, startFile, endRank, endFile int) bool {
 
 // Helper functions
 
+// GetKingSquare returns the rank and file of a king from the cache kept by setPiece
+// (-1, -1 if the side has no king)
+func (b *Board) GetKingSquare(isWhite bool) (rank, file int) {
+	square := b.KingSquare[colorIndex(Color(isWhite))]
+	return square[0], square[1]
+}
+
 // findKing returns the position of the specified color's king
-func (b *Board) findKing(isWhite bool) (rank, file int) {
+func (b *Board) findKing(color Color) (rank, file int) {
+	return b.GetKingSquare(color == White)
+}
+
+// locateKings fills the king square cache by scanning the board, after pieces were placed
+// without setPiece
+func (b *Board) locateKings() {
+	for _, color := range []Color{White, Black} {
+		rank, file := b.scanForKing(color)
+		b.KingSquare[colorIndex(color)] = [2]int{rank, file}
+	}
+}
+
+// scanForKing searches the board for the specified color's king
+func (b *Board) scanForKing(color Color) (rank, file int) {
 	kingPiece := BK
-	if isWhite {
+	if color == White {
 		kingPiece = WK
 	}
 	for r := 0; r < 8; r++ {
@@ -167,13 +181,11 @@ func (b *Board) findKing(isWhite bool) (rank, file int) {
 }
 
 // IsSquareAttacked returns true if the given square can be captured by any enemy piece
-func (b *Board) IsSquareAttacked(rank, file int, attackerIsWhite bool) bool {
+func (b *Board) IsSquareAttacked(rank, file int, attacker Color) bool {
+	attackerIsWhite := attacker == White
 
 	// Check for attacking pawns
-	direction := 1
-	if attackerIsWhite {
-		direction = -1
-	}
+	direction := attacker.PawnDirection()
 	// Check pawn captures
 	if rank-direction >= 0 && rank-direction < 8 {
 		if file-1 >= 0 {
@@ -265,85 +277,78 @@ func (b *Board) IsSquareAttacked(rank, file int, attackerIsWhite bool) bool {
 }
 
 // IsInCheck returns true if the specified color's king is in check
-func (b *Board) IsInCheck(isWhite bool) bool {
-	kingRank, kingFile := b.findKing(isWhite)
-	return b.IsSquareAttacked(kingRank, kingFile, !isWhite)
+func (b *Board) IsInCheck(color Color) bool {
+	kingRank, kingFile := b.findKing(color)
+	return b.IsSquareAttacked(kingRank, kingFile, color.Opposite())
 }
 
 // IsCheckmate returns true if the specified color is in checkmate
-func (b *Board) IsCheckmate(isWhite bool) bool {
+func (b *Board) IsCheckmate(color Color) bool {
 	// First, the king must be in check
-	if !b.IsInCheck(isWhite) {
+	if !b.IsInCheck(color) {
 		return false
 	}
 
 	// Try all possible moves for this color to see if any can escape check
-	for fromRank := 0; fromRank < 8; fromRank++ {
-		for fromFile := 0; fromFile < 8; fromFile++ {
-			piece := b.GetPiece(fromRank, fromFile)
-
-			// Skip empty squares and opponent pieces
-			if piece == Empty || (piece < BP) != isWhite {
-				continue
-			}
+	for _, loc := range b.GetAllPieces(color) {
+		fromRank, fromFile, piece := loc.Rank, loc.File, loc.Piece
+
+		// Try all possible destination squares for this piece
+		for toRank := 0; toRank < 8; toRank++ {
+			for toFile := 0; toFile < 8; toFile++ {
+				// Skip moving to the same square
+				if fromRank == toRank && fromFile == toFile {
+					continue
+				}
 
-			// Try all possible destination squares for this piece
-			for toRank := 0; toRank < 8; toRank++ {
-				for toFile := 0; toFile < 8; toFile++ {
-					// Skip moving to the same square
-					if fromRank == toRank && fromFile == toFile {
-						continue
-					}
+				// Check if this piece can legally move to this square
+				canMove := false
+				switch piece {
+				case WP, BP:
+					// Check if it's a capture
+					targetPiece := b.GetPiece(toRank, toFile)
+					isCapture := targetPiece != Empty
+					canMove = canPawnMove(b, fromRank, fromFile, toRank, toFile, isCapture)
+				case WN, BN:
+					canMove = CanKnightMove(fromRank, fromFile, toRank, toFile)
+				case WB, BB:
+					canMove = CanBishopMove(b, fromRank, fromFile, toRank, toFile)
+				case WR, BR:
+					canMove = CanRookMove(b, fromRank, fromFile, toRank, toFile)
+				case WQ, BQ:
+					canMove = CanQueenMove(b, fromRank, fromFile, toRank, toFile)
+				case WK, BK:
+					canMove = canKingMove(fromRank, fromFile, toRank, toFile)
+				}
 
-					// Check if this piece can legally move to this square
-					canMove := false
-					switch piece {
-					case WP, BP:
-						// Check if it's a capture
-						targetPiece := b.GetPiece(toRank, toFile)
-						isCapture := targetPiece != Empty
-						canMove = canPawnMove(b, fromRank, fromFile, toRank, toFile, isCapture)
-					case WN, BN:
-						canMove = CanKnightMove(fromRank, fromFile, toRank, toFile)
-					case WB, BB:
-						canMove = CanBishopMove(b, fromRank, fromFile, toRank, toFile)
-					case WR, BR:
-						canMove = CanRookMove(b, fromRank, fromFile, toRank, toFile)
-					case WQ, BQ:
-						canMove = CanQueenMove(b, fromRank, fromFile, toRank, toFile)
-					case WK, BK:
-						canMove = canKingMove(fromRank, fromFile, toRank, toFile)
-					}
+				if !canMove {
+					continue
+				}
 
-					if !canMove {
+				// Check if the destination square is valid for capture/movement
+				targetPiece := b.GetPiece(toRank, toFile)
+				if targetPiece != Empty {
+					// Can't capture own pieces
+					if ColorOf(targetPiece) == color {
 						continue
 					}
+				}
 
-					// Check if the destination square is valid for capture/movement
-					targetPiece := b.GetPiece(toRank, toFile)
-					if targetPiece != Empty {
-						// Can't capture own pieces
-						if (targetPiece < BP) == isWhite {
-							continue
-						}
-					}
-
-					// Try the move temporarily
-					originalPiece := targetPiece
-					b.Squares[toRank][toFile].Piece = piece
-					b.Squares[fromRank][fromFile].Piece = Empty
+				// Try the move temporarily
+				originalPiece := targetPiece
+				b.setPiece(toRank, toFile, piece)
+				b.setPiece(fromRank, fromFile, Empty)
 
-					// Check if the king is still in check after this move
-					stillInCheck := b.IsInCheck(isWhite)
+				// Check if the king is still in check after this move
+				stillInCheck := b.IsInCheck(color)
 
-					// Undo the move
-					b.Squares[fromRank][fromFile].Piece = piece
-					b.Squares[toRank][toFile].Piece = originalPiece
+				// Undo the move
+				b.setPiece(fromRank, fromFile, piece)
+				b.setPiece(toRank, toFile, originalPiece)
 
-					// If this move gets us out of check, it's not checkmate
-					if !stillInCheck {
-						return false
-					}
+				// If this move gets us out of check, it's not checkmate
+				if !stillInCheck {
+					return false
 				}
 			}
 		}
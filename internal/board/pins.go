@@ -0,0 +1,72 @@
+package board
+
+// PinInfo describes a piece pinned to its king by an enemy slider. RankDir and FileDir
+// step from the king towards the pinner.
+type PinInfo struct {
+	PinnedRank, PinnedFile int
+	PinnerRank, PinnerFile int
+	RankDir, FileDir       int
+}
+
+// kingRays are the eight directions a slider can attack the king from
+var kingRays = [][2]int{
+	{-1, 0}, {1, 0}, {0, -1}, {0, 1}, // Rook/queen lines
+	{-1, -1}, {-1, 1}, {1, -1}, {1, 1}, // Bishop/queen diagonals
+}
+
+// GetPinnedPieces returns the pieces of the given color that are pinned to their king
+func (b *Board) GetPinnedPieces(color Color) []PinInfo {
+	kingRank, kingFile := b.findKing(color)
+	if kingRank < 0 {
+		return nil
+	}
+
+	enemyRook, enemyBishop, enemyQueen := BR, BB, BQ
+	if color == Black {
+		enemyRook, enemyBishop, enemyQueen = WR, WB, WQ
+	}
+
+	var pins []PinInfo
+	for _, ray := range kingRays {
+		diagonal := ray[0] != 0 && ray[1] != 0
+		pinnedRank, pinnedFile := -1, -1
+
+		for rank, file := kingRank+ray[0], kingFile+ray[1]; rank >= 0 && rank < 8 && file >= 0 && file < 8; rank, file = rank+ray[0], file+ray[1] {
+			piece := b.GetPiece(rank, file)
+			if piece == Empty {
+				continue
+			}
+
+			if ColorOf(piece) == color {
+				if pinnedRank >= 0 {
+					break // Two friendly pieces shield each other
+				}
+				pinnedRank, pinnedFile = rank, file
+				continue
+			}
+
+			slides := piece == enemyQueen || (diagonal && piece == enemyBishop) || (!diagonal && piece == enemyRook)
+			if pinnedRank >= 0 && slides {
+				pins = append(pins, PinInfo{
+					PinnedRank: pinnedRank, PinnedFile: pinnedFile,
+					PinnerRank: rank, PinnerFile: file,
+					RankDir: ray[0], FileDir: ray[1],
+				})
+			}
+			break
+		}
+	}
+
+	return pins
+}
+
+// alongPin reports whether a pinned piece moving to the target square stays on its pin ray
+// (between its king and the pinner, or capturing the pinner)
+func (p PinInfo) alongPin(kingRank, kingFile, toRank, toFile int) bool {
+	rankOffset, fileOffset := toRank-kingRank, toFile-kingFile
+	if sign(rankOffset) != p.RankDir || sign(fileOffset) != p.FileDir {
+		return false
+	}
+	// On a diagonal both offsets must have the same length
+	return p.RankDir == 0 || p.FileDir == 0 || abs(rankOffset) == abs(fileOffset)
+}
@@ -0,0 +1,48 @@
+package board
+
+import "testing"
+
+func TestSANDisambiguation(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		move string
+		want string
+	}{
+		{"queens on one rank", "4k3/8/8/8/8/8/6K1/Q6Q w - - 0 1", "a1d1", "Qad1"},
+		{"queens on one rank, other queen", "4k3/8/8/8/8/8/6K1/Q6Q w - - 0 1", "h1d1", "Qhd1"},
+		{"knights by file", "4k3/8/8/8/8/5N2/8/1N2K3 w - - 0 1", "b1d2", "Nbd2"},
+		{"rooks by rank", "4k3/8/8/R7/8/8/8/R3K3 w - - 0 1", "a1a3", "R1a3"},
+		{"rooks by rank, other rook", "4k3/8/8/R7/8/8/8/R3K3 w - - 0 1", "a5a3", "R5a3"},
+		{"black knights by rank", "4k3/1n6/8/1n6/8/8/8/4K3 b - - 0 1", "b7d6", "N7d6"},
+		{"three queens, full square", "4k3/8/8/8/8/Q7/8/Q1Q1K3 w - - 0 1", "a1b2", "Qa1b2"},
+		{"three queens, file", "4k3/8/8/8/8/Q7/8/Q1Q1K3 w - - 0 1", "c1b2", "Qcb2"},
+		{"three queens, rank", "4k3/8/8/8/8/Q7/8/Q1Q1K3 w - - 0 1", "a3b2", "Q3b2"},
+		{"capture with disambiguation", "4k3/8/8/8/8/8/6K1/R2n3R w - - 0 1", "a1d1", "Raxd1"},
+		{"disambiguation and check", "3k4/8/8/8/8/8/6K1/R6R w - - 0 1", "h1d1", "Rhd1+"},
+		{"pinned knight needs none", "4k3/8/8/8/1b6/2N5/8/4K1N1 w - - 0 1", "g1e2", "Ne2"},
+		{"single piece needs none", "4k3/8/8/8/8/8/8/R3K3 w - - 0 1", "a1a3", "Ra3"},
+	}
+
+	for _, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		san, err := b.SAN(test.move)
+		if err != nil {
+			t.Errorf("%s: SAN(%s): %v", test.name, test.move, err)
+			continue
+		}
+		if san != test.want {
+			t.Errorf("%s: SAN(%s) = %q, want %q", test.name, test.move, san, test.want)
+		}
+
+		// The notation must lead back to the same move
+		if err := b.MakeMove(san); err != nil {
+			t.Errorf("%s: MakeMove(%q): %v", test.name, san, err)
+		} else if got := b.UCIMoves[len(b.UCIMoves)-1]; got != test.move {
+			t.Errorf("%s: MakeMove(%q) played %s, want %s", test.name, san, got, test.move)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package board
+
+// Zobrist keys for incremental position hashing
+var (
+	zobristPieces     [13][64]uint64 // indexed by piece constant and square index (rank*8 + file)
+	zobristCastling   [16]uint64     // indexed by the castling rights bitmask
+	zobristEnPassant  [8]uint64      // indexed by the en passant target file
+	zobristSideToMove uint64         // toggled in when black is to move
+)
+
+func init() {
+	// Fixed seed so hashes are stable across runs
+	state := uint64(0x9E3779B97F4A7C15)
+	next := func() uint64 {
+		// splitmix64
+		state += 0x9E3779B97F4A7C15
+		z := state
+		z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+		z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+		return z ^ (z >> 31)
+	}
+
+	for piece := WP; piece <= BK; piece++ {
+		for square := 0; square < 64; square++ {
+			zobristPieces[piece][square] = next()
+		}
+	}
+	for rights := range zobristCastling {
+		zobristCastling[rights] = next()
+	}
+	for file := range zobristEnPassant {
+		zobristEnPassant[file] = next()
+	}
+	zobristSideToMove = next()
+}
+
+// computeHash calculates the Zobrist hash of the position from scratch
+func (b *Board) computeHash() uint64 {
+	var hash uint64
+
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			hash ^= zobristPieces[b.GetPiece(rank, file)][rank*8+file]
+		}
+	}
+
+	hash ^= zobristCastling[b.CastlingRights.ToInt()]
+	hash ^= enPassantKey(b.EnPassant)
+	if !b.WhiteToMove {
+		hash ^= zobristSideToMove
+	}
+
+	return hash
+}
+
+// GetPawnStructureHash hashes the pawn placement alone, with the same keys as the position
+// hash, so positions with the same pawns share a key whatever the other pieces or side to move
+func (b *Board) GetPawnStructureHash() uint64 {
+	var hash uint64
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			if piece := b.GetPiece(rank, file); piece == WP || piece == BP {
+				hash ^= zobristPieces[piece][rank*8+file]
+			}
+		}
+	}
+	return hash
+}
+
+// enPassantKey returns the Zobrist key for an en passant target square (0 if none)
+func enPassantKey(square string) uint64 {
+	if square == "" {
+		return 0
+	}
+	_, file := GetSquareCoords(square)
+	if file < 0 {
+		return 0
+	}
+	return zobristEnPassant[file]
+}
+
+// setPiece places a piece on a square, keeping the position hash and king squares up to date
+func (b *Board) setPiece(rank, file, piece int) {
+	square := rank*8 + file
+	b.Hash ^= zobristPieces[b.Squares[rank][file].Piece][square]
+	b.Hash ^= zobristPieces[piece][square]
+	b.Squares[rank][file].Piece = piece
+
+	if piece == WK || piece == BK {
+		b.KingSquare[colorIndex(ColorOf(piece))] = [2]int{rank, file}
+	}
+}
+
+// setCastlingRights updates the castling rights, keeping the position hash up to date
+func (b *Board) setCastlingRights(rights CastlingRights) {
+	b.Hash ^= zobristCastling[b.CastlingRights.ToInt()]
+	b.Hash ^= zobristCastling[rights.ToInt()]
+	b.CastlingRights = rights
+}
+
+// setEnPassant updates the en passant target, keeping the position hash up to date
+func (b *Board) setEnPassant(square string) {
+	b.Hash ^= enPassantKey(b.EnPassant)
+	b.Hash ^= enPassantKey(square)
+	b.EnPassant = square
+}
+
+// UndoInfo holds the state changed by MakeNullMove
+type UndoInfo struct {
+	WhiteToMove bool
+	EnPassant   string
+	Hash        uint64
+}
+
+// MakeNullMove passes the turn without moving a piece, clearing the en passant target.
+// Pieces, castling rights, clocks and histories are untouched; undo it with UndoNullMove.
+func (b *Board) MakeNullMove() UndoInfo {
+	undo := UndoInfo{WhiteToMove: b.WhiteToMove, EnPassant: b.EnPassant, Hash: b.Hash}
+	b.setEnPassant("")
+	b.switchSide()
+	return undo
+}
+
+// UndoNullMove restores the position before a MakeNullMove
+func (b *Board) UndoNullMove(undo UndoInfo) {
+	b.WhiteToMove = undo.WhiteToMove
+	b.EnPassant = undo.EnPassant
+	b.Hash = undo.Hash
+}
+
+// switchSide passes the turn to the other player, keeping the position hash up to date
+func (b *Board) switchSide() {
+	b.WhiteToMove = !b.WhiteToMove
+	b.Hash ^= zobristSideToMove
+}
@@ -0,0 +1,173 @@
+// Package config loads the server configuration from flags, environment variables and a JSON file
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Config is the server configuration
+type Config struct {
+	Port          int    `json:"port"`          // HTTP listen port
+	StaticDir     string `json:"staticDir"`     // Directory served under /static/
+	TemplateDir   string `json:"templateDir"`   // Directory holding index.html
+	EnginePath    string `json:"enginePath"`    // Stockfish binary, used when CHESS_ENGINES is not set
+	EngineThreads int    `json:"engineThreads"` // Search threads per engine
+	EngineHashMB  int    `json:"engineHashMB"`  // Transposition table size per engine, in MB
+	LogLevel      string `json:"logLevel"`      // debug, info, warn or error
+	EnableDebug   bool   `json:"enableDebug"`   // Print the effective configuration and enable diagnostics
+}
+
+// Default returns the built-in configuration, matching the Docker image layout
+func Default() Config {
+	return Config{
+		Port:        8080,
+		StaticDir:   "web/static/",
+		TemplateDir: "web/templates/",
+		EnginePath:  "/usr/local/bin/stockfish",
+		LogLevel:    "info",
+
+		// Half the cores leaves room for the server and background analysis engines
+		EngineThreads: max(1, runtime.NumCPU()/2),
+		EngineHashMB:  256,
+	}
+}
+
+// setting is one configuration value with its flag and environment variable names
+type setting struct {
+	flag, env, usage string
+	set              func(c *Config, value string) error
+}
+
+// flagValue records the raw value of a flag so it can be applied after the other sources
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (v *flagValue) String() string     { return v.value }
+func (v *flagValue) Set(s string) error { v.value = s; return nil }
+func (v *flagValue) IsBoolFlag() bool   { return v.isBool }
+
+var settings = []setting{
+	{"port", "CHESS_PORT", "HTTP listen port", func(c *Config, value string) error {
+		port, err := strconv.Atoi(value)
+		c.Port = port
+		return err
+	}},
+	{"static-dir", "CHESS_STATIC_DIR", "directory of static assets", func(c *Config, value string) error {
+		c.StaticDir = value
+		return nil
+	}},
+	{"template-dir", "CHESS_TEMPLATE_DIR", "directory of HTML templates", func(c *Config, value string) error {
+		c.TemplateDir = value
+		return nil
+	}},
+	{"engine-path", "CHESS_ENGINE_PATH", "path of the Stockfish binary", func(c *Config, value string) error {
+		c.EnginePath = value
+		return nil
+	}},
+	{"engine-threads", "CHESS_ENGINE_THREADS", "search threads per engine", func(c *Config, value string) error {
+		threads, err := strconv.Atoi(value)
+		c.EngineThreads = threads
+		return err
+	}},
+	{"engine-hash-mb", "CHESS_ENGINE_HASH_MB", "engine hash table size in MB", func(c *Config, value string) error {
+		hashMB, err := strconv.Atoi(value)
+		c.EngineHashMB = hashMB
+		return err
+	}},
+	{"log-level", "CHESS_LOG_LEVEL", "log level (debug, info, warn, error)", func(c *Config, value string) error {
+		c.LogLevel = value
+		return nil
+	}},
+	{"enable-debug", "CHESS_ENABLE_DEBUG", "print the effective configuration and enable diagnostics", func(c *Config, value string) error {
+		debug, err := strconv.ParseBool(value)
+		c.EnableDebug = debug
+		return err
+	}},
+}
+
+// Load builds the configuration from, in increasing priority: the defaults, the JSON file
+// named by --config (or CHESS_CONFIG), environment variables and command-line flags
+func Load(args []string) (Config, error) {
+	flags := flag.NewFlagSet("chess-engine", flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv("CHESS_CONFIG"), "JSON configuration file")
+	flagValues := make(map[string]*flagValue, len(settings))
+	for _, s := range settings {
+		flagValues[s.flag] = &flagValue{isBool: s.flag == "enable-debug"}
+		flags.Var(flagValues[s.flag], s.flag, fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			return Config{}, fmt.Errorf("reading config file: %v", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("parsing config file %s: %v", *configPath, err)
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.env); ok {
+			if err := s.set(&cfg, value); err != nil {
+				return Config{}, fmt.Errorf("invalid %s %q: %v", s.env, value, err)
+			}
+		}
+	}
+
+	// Only flags given on the command line override the other sources
+	var flagErr error
+	flags.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && flagErr == nil {
+				if err := s.set(&cfg, flagValues[s.flag].value); err != nil {
+					flagErr = fmt.Errorf("invalid -%s %q: %v", s.flag, flagValues[s.flag].value, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return Config{}, flagErr
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate checks the values of a configuration
+func (c Config) Validate() error {
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("port %d out of range", c.Port)
+	}
+	if c.EngineThreads < 1 {
+		return fmt.Errorf("engine threads must be at least 1, got %d", c.EngineThreads)
+	}
+	if c.EngineHashMB < 1 {
+		return fmt.Errorf("engine hash size must be at least 1 MB, got %d", c.EngineHashMB)
+	}
+	if _, err := c.SlogLevel(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SlogLevel returns the log level as a slog level
+func (c Config) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToUpper(c.LogLevel))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", c.LogLevel)
+	}
+	return level, nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadEnableDebug(t *testing.T) {
+	t.Setenv("CHESS_ENABLE_DEBUG", "") // Restored after the test
+
+	tests := []struct {
+		name string
+		env  string
+		args []string
+		want bool
+	}{
+		{"default", "", nil, false},
+		{"flag", "", []string{"--enable-debug"}, true},
+		{"single dash flag", "", []string{"-enable-debug"}, true},
+		{"flag with value", "", []string{"--enable-debug=false"}, false},
+		{"environment", "true", nil, true},
+		{"flag overrides environment", "true", []string{"--enable-debug=false"}, false},
+	}
+
+	for _, test := range tests {
+		if test.env != "" {
+			os.Setenv("CHESS_ENABLE_DEBUG", test.env)
+		} else {
+			os.Unsetenv("CHESS_ENABLE_DEBUG")
+		}
+		cfg, err := Load(test.args)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if cfg.EnableDebug != test.want {
+			t.Errorf("%s: EnableDebug = %v, want %v", test.name, cfg.EnableDebug, test.want)
+		}
+	}
+
+	if _, err := Load([]string{"--debug"}); err == nil {
+		t.Error("the old --debug flag is still accepted")
+	}
+}
@@ -0,0 +1,159 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/uci"
+)
+
+// Move classifications used by AnnotateGame
+const (
+	ClassBrilliant  = "brilliant"
+	ClassGood       = "good"
+	ClassNeutral    = "neutral"
+	ClassInaccuracy = "inaccuracy"
+	ClassMistake    = "mistake"
+	ClassBlunder    = "blunder"
+)
+
+// Further evaluation swings used by AnnotateGame, next to the BlunderThreshold and MistakeThreshold of BuildHistory
+const (
+	InaccuracyThreshold = -50
+	GoodThreshold       = 50
+)
+
+// AnnotatedMove is one half-move of a reviewed game
+type AnnotatedMove struct {
+	UCI            string `json:"uci"`
+	SAN            string `json:"san"`
+	EvalBefore     int    `json:"evalBefore"` // Evaluation before the move from White's perspective
+	EvalAfter      int    `json:"evalAfter"`  // Evaluation after the move from White's perspective
+	EvalDelta      int    `json:"evalDelta"`  // Evaluation change from the mover's perspective
+	Classification string `json:"classification"`
+}
+
+// positionReview is the engine's view of a position, scores for the side to move
+type positionReview struct {
+	eval     int    // Best score
+	bestMove string // Engine's best move, empty if the game is over
+	margin   int    // How much worse the second best move is; 0 if there is only one line
+}
+
+// AnnotateGame replays a game given in UCI moves from the starting position, evaluating each
+// position at the given depth and classifying every move by how it changed the evaluation.
+// If a move can't be replayed or evaluated, the moves annotated so far are returned.
+func AnnotateGame(moves []string, engine *uci.Engine, depth int) []AnnotatedMove {
+	annotated, _ := AnnotateGameContext(context.Background(), moves, engine, depth, nil)
+	return annotated
+}
+
+// AnnotateGameContext is AnnotateGame for background jobs: it stops when ctx is cancelled and
+// reports its progress after every half-move (progress may be nil). On error it returns the
+// moves annotated so far along with the error.
+func AnnotateGameContext(ctx context.Context, moves []string, engine *uci.Engine, depth int, progress HistoryProgress) ([]AnnotatedMove, error) {
+	annotated := []AnnotatedMove{}
+	if engine == nil {
+		return annotated, fmt.Errorf("no engine available")
+	}
+
+	replay := board.NewBoard()
+	before, err := reviewPosition(replay, engine, depth)
+	if err != nil {
+		return annotated, err
+	}
+
+	for i, uciMove := range moves {
+		if err := ctx.Err(); err != nil {
+			return annotated, err
+		}
+
+		isWhite := replay.WhiteToMove
+		if err := replay.MakeUCIMove(uciMove); err != nil {
+			return annotated, fmt.Errorf("failed to replay move %d (%s): %v", i+1, uciMove, err)
+		}
+
+		after, err := reviewPosition(replay, engine, depth)
+		if err != nil {
+			return annotated, err
+		}
+
+		// Scores are for the side to move, so the position after the move is seen by the opponent
+		evalBefore, evalAfter := before.eval, -after.eval
+		delta := evalAfter - evalBefore
+
+		move := AnnotatedMove{
+			UCI:            uciMove,
+			SAN:            replay.MovesPlayed[len(replay.MovesPlayed)-1],
+			EvalBefore:     evalBefore,
+			EvalAfter:      evalAfter,
+			EvalDelta:      delta,
+			Classification: classifyMove(delta, uciMove, before),
+		}
+		if !isWhite {
+			move.EvalBefore, move.EvalAfter = -evalBefore, -evalAfter
+		}
+		annotated = append(annotated, move)
+
+		before = after
+		if progress != nil {
+			progress(i+1, len(moves))
+		}
+	}
+
+	return annotated, nil
+}
+
+// classifyMove grades a move by the evaluation change for the mover. A move that keeps the
+// evaluation is brilliant when it was the only one to do so: the engine's choice, with every
+// alternative at least a blunder worse.
+func classifyMove(delta int, uciMove string, before positionReview) string {
+	switch {
+	case delta <= BlunderThreshold:
+		return ClassBlunder
+	case delta <= MistakeThreshold:
+		return ClassMistake
+	case delta <= InaccuracyThreshold:
+		return ClassInaccuracy
+	case uciMove == before.bestMove && before.margin >= -BlunderThreshold:
+		return ClassBrilliant
+	case delta >= GoodThreshold:
+		return ClassGood
+	default:
+		return ClassNeutral
+	}
+}
+
+// reviewPosition searches the two best lines of a position. Finished games are scored
+// without the engine: checkmate as mated, other draws as 0.
+func reviewPosition(b *board.Board, engine *uci.Engine, depth int) (positionReview, error) {
+	if len(b.GetLegalMoves()) == 0 {
+		if b.IsInCheck(b.SideToMove()) {
+			return positionReview{eval: uci.MatedScore}, nil
+		}
+		return positionReview{}, nil
+	}
+
+	lines, err := engine.GetMultiPVAnalysis(b.ToFEN(), depth, 2)
+	if err != nil {
+		return positionReview{}, fmt.Errorf("failed to evaluate position: %v", err)
+	}
+	if len(lines) == 0 || len(lines[0].PV) == 0 {
+		return positionReview{}, fmt.Errorf("engine returned no analysis")
+	}
+
+	review := positionReview{eval: lineScore(lines[0]), bestMove: lines[0].PV[0]}
+	if len(lines) > 1 {
+		review.margin = review.eval - lineScore(lines[1])
+	}
+	return review, nil
+}
+
+// lineScore returns the score of an analysis line in centipawns, encoding mates with uci.MateToScore
+func lineScore(line uci.MultiPVLine) int {
+	if line.Mate != 0 {
+		return uci.MateToScore(line.Mate)
+	}
+	return line.Score
+}
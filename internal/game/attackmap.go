@@ -0,0 +1,40 @@
+package game
+
+import "github.com/zully/chess-engine/internal/board"
+
+// AttackMap lists the squares each side controls
+type AttackMap struct {
+	White     []string `json:"white"`     // Squares attacked by White
+	Black     []string `json:"black"`     // Squares attacked by Black
+	Contested []string `json:"contested"` // Squares attacked by both sides
+	Defended  []string `json:"defended"`  // Squares of pieces defended by their own side
+}
+
+// NewAttackMap checks every square of the board for attacks by either side
+func NewAttackMap(gameBoard *board.Board) AttackMap {
+	attackMap := AttackMap{White: []string{}, Black: []string{}, Contested: []string{}, Defended: []string{}}
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			square := board.GetSquareName(rank, file)
+			byWhite := gameBoard.IsSquareAttacked(rank, file, board.White)
+			byBlack := gameBoard.IsSquareAttacked(rank, file, board.Black)
+
+			if byWhite {
+				attackMap.White = append(attackMap.White, square)
+			}
+			if byBlack {
+				attackMap.Black = append(attackMap.Black, square)
+			}
+			if byWhite && byBlack {
+				attackMap.Contested = append(attackMap.Contested, square)
+			}
+
+			if piece := gameBoard.GetPiece(rank, file); piece != board.Empty {
+				if owner := board.ColorOf(piece); owner == board.White && byWhite || owner == board.Black && byBlack {
+					attackMap.Defended = append(attackMap.Defended, square)
+				}
+			}
+		}
+	}
+	return attackMap
+}
@@ -0,0 +1,157 @@
+package game
+
+import "time"
+
+// GameClock is a chess clock with an increment (Fischer) added after every move
+type GameClock struct {
+	WhiteMs     int64     // White's remaining time when its clock last stopped
+	BlackMs     int64     // Black's remaining time when its clock last stopped
+	IncrementMs int64     // Added to the mover's time after each move
+	StartedAt   time.Time // When the running clock was started
+	Running     bool
+	ActiveColor bool // True while White's clock is the one running
+
+	WhiteTimedOut bool
+	BlackTimedOut bool
+}
+
+// ClockState is the clock as reported in GameState
+type ClockState struct {
+	WhiteMs       int64 `json:"whiteMs"`
+	BlackMs       int64 `json:"blackMs"`
+	IncrementMs   int64 `json:"incrementMs"`
+	Running       bool  `json:"running"`
+	WhiteTimedOut bool  `json:"whiteTimedOut"`
+	BlackTimedOut bool  `json:"blackTimedOut"`
+}
+
+// NewGameClock creates a stopped clock with the given times in milliseconds
+func NewGameClock(whiteMs, blackMs, incrementMs int64) *GameClock {
+	return &GameClock{WhiteMs: whiteMs, BlackMs: blackMs, IncrementMs: incrementMs}
+}
+
+// Start runs the clock of the given side
+func (c *GameClock) Start(isWhite bool) {
+	if c.TimedOut() {
+		return
+	}
+	c.ActiveColor = isWhite
+	c.StartedAt = time.Now()
+	c.Running = true
+}
+
+// Stop stops the running clock, deducting the time used, and returns the elapsed milliseconds
+func (c *GameClock) Stop() int64 {
+	if !c.Running {
+		return 0
+	}
+	elapsed := time.Since(c.StartedAt).Milliseconds()
+	c.Running = false
+
+	remaining := c.remainingMs(c.ActiveColor) - elapsed
+	if remaining <= 0 {
+		remaining = 0
+		c.flag(c.ActiveColor)
+	}
+	if c.ActiveColor {
+		c.WhiteMs = remaining
+	} else {
+		c.BlackMs = remaining
+	}
+	return elapsed
+}
+
+// OnMoveMade charges the mover for the move, adds the increment and starts the opponent's clock.
+// A move made after the mover's time ran out doesn't get the increment, and the clock stays stopped.
+func (c *GameClock) OnMoveMade(isWhite bool) {
+	if !c.Running || c.ActiveColor != isWhite {
+		return
+	}
+
+	c.Stop()
+	if c.TimedOut() {
+		return
+	}
+	if isWhite {
+		c.WhiteMs += c.IncrementMs
+	} else {
+		c.BlackMs += c.IncrementMs
+	}
+	c.Start(!isWhite)
+}
+
+// CheckFlag stops the clock if the running side has run out of time, and reports whether
+// either side has
+func (c *GameClock) CheckFlag() bool {
+	if c.Running && c.RemainingMs(c.ActiveColor) <= 0 {
+		c.Stop()
+	}
+	return c.TimedOut()
+}
+
+// TimedOut reports whether either side has run out of time
+func (c *GameClock) TimedOut() bool {
+	return c.WhiteTimedOut || c.BlackTimedOut
+}
+
+// RemainingMs returns a side's remaining time, counting the time used so far if its clock is running
+func (c *GameClock) RemainingMs(isWhite bool) int64 {
+	remaining := c.remainingMs(isWhite)
+	if c.Running && c.ActiveColor == isWhite {
+		remaining -= time.Since(c.StartedAt).Milliseconds()
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// State returns the clock's current readings
+func (c *GameClock) State() ClockState {
+	return ClockState{
+		WhiteMs:       c.RemainingMs(true),
+		BlackMs:       c.RemainingMs(false),
+		IncrementMs:   c.IncrementMs,
+		Running:       c.Running,
+		WhiteTimedOut: c.WhiteTimedOut,
+		BlackTimedOut: c.BlackTimedOut,
+	}
+}
+
+// remainingMs returns a side's time as of the last time its clock stopped
+func (c *GameClock) remainingMs(isWhite bool) int64 {
+	if isWhite {
+		return c.WhiteMs
+	}
+	return c.BlackMs
+}
+
+// flag records that a side has run out of time
+func (c *GameClock) flag(isWhite bool) {
+	if isWhite {
+		c.WhiteTimedOut = true
+	} else {
+		c.BlackTimedOut = true
+	}
+}
+
+// ApplyClock adds the clock readings to the state and ends the game when a side has run out
+// of time: the other side wins, with no draw reason
+func (s *GameState) ApplyClock(clock *GameClock) {
+	if clock == nil {
+		return
+	}
+
+	clock.CheckFlag()
+	state := clock.State()
+	s.Clock = &state
+
+	if !s.GameOver && clock.TimedOut() {
+		s.GameOver = true
+		s.DrawReason = ""
+		s.Result = ResultBlackWins
+		if clock.BlackTimedOut {
+			s.Result = ResultWhiteWins
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/uci"
+)
+
+// GameEventType identifies the kind of a game event
+type GameEventType string
+
+const (
+	EventMovePlayed GameEventType = "move-played" // Data: san, uci, side, engine, engineName (engine moves)
+	EventEngineInfo GameEventType = "engine-info" // Data: depth, score, pv, mateIn (for mate scores)
+	EventCheck      GameEventType = "check"       // Data: side
+	EventCheckmate  GameEventType = "checkmate"   // Data: winner
+	EventDraw       GameEventType = "draw"        // Data: reason
+	EventTurn       GameEventType = "turn"        // Data: side
+	EventUndo       GameEventType = "undo"        // Data: moves
+	EventReset      GameEventType = "reset"
+)
+
+// GameEvent is a structured description of something that happened in the game,
+// so clients can render or localize it instead of parsing Message
+type GameEvent struct {
+	Type GameEventType          `json:"type"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// MovePlayedEvent reports a move made by the player or the engine. engineName names the
+// engine that played the move, and is empty for the player's moves.
+func MovePlayedEvent(san, uciMove string, side board.Color, engineName string) GameEvent {
+	event := GameEvent{Type: EventMovePlayed, Data: map[string]interface{}{
+		"san":    san,
+		"uci":    uciMove,
+		"side":   side.String(),
+		"engine": engineName != "",
+	}}
+	if engineName != "" {
+		event.Data["engineName"] = engineName
+	}
+	return event
+}
+
+// EngineInfoEvent reports the search information behind an engine move. For mate scores
+// mateIn is the number of moves to mate, negative when the engine is getting mated.
+func EngineInfoEvent(depth, score int, pv []string) GameEvent {
+	event := GameEvent{Type: EventEngineInfo, Data: map[string]interface{}{
+		"depth": depth,
+		"score": score,
+		"pv":    pv,
+	}}
+	if uci.IsMateScore(score) {
+		mateIn := uci.MateInMoves(score)
+		if score < 0 {
+			mateIn = -mateIn
+		}
+		event.Data["mateIn"] = mateIn
+	}
+	return event
+}
+
+// UndoEvent reports the moves taken back by an undo
+func UndoEvent(undone []string) GameEvent {
+	return GameEvent{Type: EventUndo, Data: map[string]interface{}{
+		"moves": undone,
+	}}
+}
+
+// ResetEvent reports that a new game was started
+func ResetEvent() GameEvent {
+	return GameEvent{Type: EventReset}
+}
+
+// PositionEvents describes the status of the current position: checkmate, check, draw or whose turn it is
+func PositionEvents(gameBoard *board.Board) []GameEvent {
+	return outcomeEvents(DeriveOutcome(gameBoard), gameBoard.SideToMove())
+}
+
+// outcomeEvents describes an outcome for the side to move
+func outcomeEvents(outcome Outcome, toMove board.Color) []GameEvent {
+	if outcome.IsCheckmate {
+		return []GameEvent{{Type: EventCheckmate, Data: map[string]interface{}{"winner": toMove.Opposite().String()}}}
+	}
+	if outcome.InCheck {
+		return []GameEvent{{Type: EventCheck, Data: map[string]interface{}{"side": toMove.String()}}}
+	}
+	if outcome.Draw {
+		return []GameEvent{{Type: EventDraw, Data: map[string]interface{}{"reason": outcome.DrawReason}}}
+	}
+	return []GameEvent{{Type: EventTurn, Data: map[string]interface{}{"side": toMove.String()}}}
+}
+
+// FormatMessage renders events as the English status message - the single source of message wording
+func FormatMessage(events []GameEvent) string {
+	var lead, status, shortStatus string
+	engineMove := false
+
+	for _, event := range events {
+		switch event.Type {
+		case EventMovePlayed:
+			if engine, _ := event.Data["engine"].(bool); engine {
+				name, _ := event.Data["engineName"].(string)
+				if name == "" {
+					name = "Engine"
+				}
+				lead = fmt.Sprintf("%s played %v", name, event.Data["san"])
+				engineMove = true
+			}
+		case EventEngineInfo:
+			score := fmt.Sprintf("score: %v", event.Data["score"])
+			if mateIn, ok := event.Data["mateIn"].(int); ok && mateIn > 0 {
+				score = fmt.Sprintf("Mate in %d!", mateIn)
+			} else if ok {
+				score = fmt.Sprintf("mated in %d", -mateIn)
+			}
+			lead += fmt.Sprintf(" (depth: %v, %s%s)", event.Data["depth"], score, formatPV(event.Data["pv"]))
+		case EventUndo:
+			if undone, _ := event.Data["moves"].([]string); len(undone) == 1 {
+				lead = fmt.Sprintf("Undid move %s", undone[0])
+			} else if len(undone) > 1 {
+				lead = fmt.Sprintf("Undid %d moves: %s", len(undone), strings.Join(undone, " "))
+			}
+		case EventReset:
+			lead = "Game reset."
+		case EventCheckmate:
+			winner := capitalize(fmt.Sprint(event.Data["winner"]))
+			status = fmt.Sprintf("Checkmate! %s wins!", winner)
+			shortStatus = fmt.Sprintf("%s wins!", winner)
+		case EventCheck:
+			side := capitalize(fmt.Sprint(event.Data["side"]))
+			status = fmt.Sprintf("%s is in check!", side)
+			shortStatus = fmt.Sprintf("%s in check!", side)
+		case EventDraw:
+			status = fmt.Sprintf("Draw! %v", event.Data["reason"])
+			shortStatus = status
+		case EventTurn:
+			status = fmt.Sprintf("%s to move", capitalize(fmt.Sprint(event.Data["side"])))
+		}
+	}
+
+	switch {
+	case lead == "":
+		return status
+	case engineMove:
+		// Engine moves keep their search info and append only noteworthy status
+		if shortStatus != "" {
+			return lead + " - " + shortStatus
+		}
+		return lead
+	case status == "":
+		return lead
+	case strings.HasSuffix(lead, "."):
+		return lead + " " + status
+	default:
+		return lead + ". " + status
+	}
+}
+
+// formatPV renders up to the first three moves of a principal variation
+func formatPV(value interface{}) string {
+	pv, _ := value.([]string)
+	if len(pv) <= 1 {
+		return ""
+	}
+
+	pvLen := len(pv)
+	if pvLen > 3 {
+		pvLen = 3
+	}
+	pvInfo := fmt.Sprintf(", PV: %s", strings.Join(pv[:pvLen], " "))
+	if len(pv) > 3 {
+		pvInfo += "..."
+	}
+	return pvInfo
+}
+
+// capitalize upper-cases the first letter of a side name
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
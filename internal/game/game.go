@@ -4,26 +4,133 @@ import (
 	"fmt"
 
 	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/opening"
 	"github.com/zully/chess-engine/internal/uci"
 )
 
 // GameState represents the complete state of a chess game
 type GameState struct {
-	Board            *board.Board    `json:"board"`
-	Message          string          `json:"message"`
-	Error            string          `json:"error,omitempty"`
-	GameOver         bool            `json:"gameOver"`
-	InCheck          bool            `json:"inCheck"`
-	IsCheckmate      bool            `json:"isCheckmate"`
-	Draw             bool            `json:"draw"`
-	DrawReason       string          `json:"drawReason"`
-	ThreefoldRep     bool            `json:"threefoldRepetition"`
-	PositionCount    int             `json:"positionCount"`
-	Evaluation       int             `json:"evaluation"`       // Position evaluation in centipawns
-	CapturedWhite    []CapturedPiece `json:"capturedWhite"`    // Pieces captured by White
-	CapturedBlack    []CapturedPiece `json:"capturedBlack"`    // Pieces captured by Black
-	StockfishVersion string          `json:"stockfishVersion"` // Stockfish engine version
-	LastUCIMove      string          `json:"lastUCIMove"`      // Last UCI move played
+	Board            *board.Board         `json:"board"`
+	Message          string               `json:"message"` // English rendering of Events
+	Events           []GameEvent          `json:"events"`
+	Error            string               `json:"error,omitempty"`
+	GameOver         bool                 `json:"gameOver"`
+	Result           string               `json:"result"`            // "1-0", "0-1", "1/2-1/2" or "*" while the game is in progress
+	Opening          string               `json:"opening,omitempty"` // Name of the opening reached, if known
+	ECO              string               `json:"eco,omitempty"`     // ECO code of the opening reached
+	InCheck          bool                 `json:"inCheck"`
+	IsCheckmate      bool                 `json:"isCheckmate"`
+	Draw             bool                 `json:"draw"`
+	DrawReason       string               `json:"drawReason"`
+	ThreefoldRep     bool                 `json:"threefoldRepetition"`
+	DrawishEndgame   bool                 `json:"drawishEndgame"` // Drawn with correct defense, but mate is still possible so the game goes on
+	LegalMoveCount   int                  `json:"legalMoveCount"` // Legal moves for the side to move
+	CanCastle        CastlingAvailability `json:"canCastle"`      // Castling moves legal right now, per side
+	PositionCount    int                  `json:"positionCount"`
+	FiftyMoveCounter int                  `json:"fiftyMoveCounter"`          // Half-moves since the last pawn move or capture; drawn at 100
+	Evaluation       int                  `json:"evaluation"`                // Position evaluation in centipawns from White's perspective
+	EvaluationDepth  int                  `json:"evaluationDepth"`           // Search depth behind Evaluation (0 for a static evaluation)
+	HasEvaluation    bool                 `json:"hasEvaluation"`             // False when no engine evaluation is available
+	CapturedWhite    []CapturedPiece      `json:"capturedWhite"`             // Pieces captured by White
+	CapturedBlack    []CapturedPiece      `json:"capturedBlack"`             // Pieces captured by Black
+	StockfishVersion string               `json:"stockfishVersion"`          // Stockfish engine version
+	LastUCIMove      string               `json:"lastUCIMove"`               // Last UCI move played
+	EngineConfig     *EngineConfig        `json:"engineConfig,omitempty"`    // Current engine strength settings
+	UndoneCount      int                  `json:"undoneCount,omitempty"`     // Number of half-moves removed by an undo
+	UndoneNotations  []string             `json:"undoneNotations,omitempty"` // Notation of the moves removed by an undo
+	Stats            *GameStats           `json:"stats,omitempty"`           // End-of-game summary, set once the game is over
+	Clock            *ClockState          `json:"clock,omitempty"`           // Remaining times, when the game is timed
+	AttackMap        *AttackMap           `json:"attackMap,omitempty"`       // Squares controlled by each side, on request
+}
+
+// EngineConfig holds the persistent engine strength and search settings
+type EngineConfig struct {
+	Elo        int    `json:"elo"`        // Target ELO rating (1350-2850), used when UseElo is set
+	Depth      int    `json:"depth"`      // Search depth for engine moves (1-15)
+	MoveTimeMs int    `json:"moveTimeMs"` // Optional time limit per engine move (0 = depth only)
+	UseElo     bool   `json:"useElo"`     // Limit engine strength to Elo
+	MultiPV    int    `json:"multiPV"`    // Number of lines shown in analysis (1-5)
+	Style      string `json:"style"`      // Playing style: solid, balanced or aggressive
+	EngineID   string `json:"engineId"`   // Configured engine to play and analyze with (see /api/engines)
+}
+
+// Engine playing styles, from most to least willing to accept a draw
+const (
+	StyleSolid      = "solid"
+	StyleBalanced   = "balanced"
+	StyleAggressive = "aggressive"
+)
+
+// StyleContempt returns the draw contempt in centipawns for a playing style
+// (positive values make the engine avoid draws)
+func StyleContempt(style string) int {
+	switch style {
+	case StyleSolid:
+		return -20
+	case StyleAggressive:
+		return 50
+	default:
+		return 0
+	}
+}
+
+// ChooseLineWithContempt picks the best analysis line when drawn lines are valued at -contempt
+func ChooseLineWithContempt(lines []uci.MultiPVLine, contempt int) (uci.MultiPVLine, bool) {
+	best := -1
+	bestScore := 0
+	for i, line := range lines {
+		if len(line.PV) == 0 || len(line.PV[0]) < 4 {
+			continue
+		}
+
+		score := line.Score
+		switch {
+		case line.Mate != 0:
+			score = uci.MateToScore(line.Mate)
+		case line.Score == 0:
+			score = -contempt // Drawn (or dead equal) line
+		}
+
+		if best < 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	if best < 0 {
+		return uci.MultiPVLine{}, false
+	}
+	return lines[best], true
+}
+
+// DefaultEngineConfig returns the default settings: full strength at depth 6
+func DefaultEngineConfig() EngineConfig {
+	return EngineConfig{
+		Elo:     0,
+		Depth:   6,
+		UseElo:  false,
+		MultiPV: 3,
+		Style:   StyleBalanced,
+	}
+}
+
+// Validate checks that the engine settings are within the supported ranges
+func (c EngineConfig) Validate() error {
+	if c.Depth < 1 || c.Depth > 15 {
+		return fmt.Errorf("depth must be between 1 and 15")
+	}
+	if c.UseElo && (c.Elo < 1350 || c.Elo > 2850) {
+		return fmt.Errorf("ELO rating %d out of range (1350-2850)", c.Elo)
+	}
+	if c.MoveTimeMs < 0 {
+		return fmt.Errorf("move time cannot be negative")
+	}
+	if c.MultiPV < 1 || c.MultiPV > 5 {
+		return fmt.Errorf("multiPV must be between 1 and 5")
+	}
+	if c.Style != StyleSolid && c.Style != StyleBalanced && c.Style != StyleAggressive {
+		return fmt.Errorf("style must be solid, balanced or aggressive")
+	}
+	return nil
 }
 
 // CapturedPiece represents a captured piece with its value
@@ -32,66 +139,66 @@ type CapturedPiece struct {
 	Value int    `json:"value"`
 }
 
+// MoveRequest represents a request to make a move
+type MoveRequest struct {
+	Move string `json:"move"` // UCI format (e.g., "e2e4", "a1e1")
+}
+
 // EngineRequest represents a request to the chess engine
 type EngineRequest struct {
-	Depth int `json:"depth,omitempty"`
-	Elo   int `json:"elo,omitempty"` // Target ELO rating (1350-2850, 0 = full strength)
+	Depth  int    `json:"depth,omitempty"`
+	Elo    int    `json:"elo,omitempty"`    // Target ELO rating (1350-2850, 0 = full strength)
+	Threat bool   `json:"threat,omitempty"` // Also report what the opponent is threatening
+	FEN    string `json:"fen,omitempty"`    // Position to analyze instead of the current game
 }
 
-// GetCapturedPieces analyzes the board and returns lists of captured pieces
+// GetCapturedPieces returns the pieces captured by each side, in the order they were taken
 func GetCapturedPieces(gameBoard *board.Board) ([]CapturedPiece, []CapturedPiece) {
-	// Initial piece counts for a standard chess game
-	initialCounts := map[int]int{
-		board.WP: 8, board.WN: 2, board.WB: 2, board.WR: 2, board.WQ: 1, board.WK: 1,
-		board.BP: 8, board.BN: 2, board.BB: 2, board.BR: 2, board.BQ: 1, board.BK: 1,
-	}
-
-	// Count current pieces on the board
-	currentCounts := make(map[int]int)
-	for rank := 0; rank < 8; rank++ {
-		for file := 0; file < 8; file++ {
-			piece := gameBoard.GetPiece(rank, file)
-			if piece != board.Empty {
-				currentCounts[piece]++
-			}
-		}
-	}
-
 	var capturedWhite []CapturedPiece // Pieces captured by White (black pieces taken)
 	var capturedBlack []CapturedPiece // Pieces captured by Black (white pieces taken)
 
-	// Check what pieces are missing (captured)
-	for pieceType, initialCount := range initialCounts {
-		currentCount := currentCounts[pieceType]
-		capturedCount := initialCount - currentCount
-
-		if capturedCount > 0 {
-			pieceTypeStr := board.GetPieceType(pieceType)
-			pieceValue := board.GetPieceValue(pieceType)
-
-			// Add each captured piece individually to the appropriate list
-			for i := 0; i < capturedCount; i++ {
-				capturedPiece := CapturedPiece{
-					Type:  pieceTypeStr,
-					Value: pieceValue,
-				}
-
-				// If it's a white piece that's missing, black captured it
-				// If it's a black piece that's missing, white captured it
-				if pieceType < board.BP { // White piece captured by black
-					capturedBlack = append(capturedBlack, capturedPiece)
-				} else { // Black piece captured by white
-					capturedWhite = append(capturedWhite, capturedPiece)
-				}
-			}
+	for _, capture := range gameBoard.CaptureHistory {
+		capturedPiece := CapturedPiece{
+			Type:  board.GetPieceType(capture.CapturedPiece),
+			Value: board.GetPieceValue(capture.CapturedPiece),
+		}
+
+		if board.ColorOf(capture.CapturedPiece) == board.White {
+			capturedBlack = append(capturedBlack, capturedPiece)
+		} else {
+			capturedWhite = append(capturedWhite, capturedPiece)
 		}
 	}
 
 	return capturedWhite, capturedBlack
 }
 
-// CreateCompleteGameState creates a complete game state with all necessary information
-func CreateCompleteGameState(gameBoard *board.Board, message string, evaluation int, stockfishEngine *uci.Engine) GameState {
+// EvaluatePosition returns the engine evaluation of the current position in centipawns
+// from White's perspective, along with the depth behind it: 0 for the engine's static
+// evaluation, uci.EvaluationDepth when the position needed a search (in check, or an engine
+// without "eval"). Every endpoint evaluates positions through here so their numbers agree.
+// ok is false when no engine is available or the evaluation failed
+func EvaluatePosition(gameBoard *board.Board, stockfishEngine *uci.Engine) (evaluation int, depth int, ok bool) {
+	if stockfishEngine == nil {
+		return 0, 0, false
+	}
+
+	eval, depth, err := stockfishEngine.GetStaticEval(gameBoard.ToFEN())
+	if err != nil {
+		return 0, 0, false
+	}
+
+	// The engine reports scores for the side to move
+	if !gameBoard.WhiteToMove {
+		eval = -eval
+	}
+
+	return eval, depth, true
+}
+
+// CreateCompleteGameState creates a complete game state with all necessary information.
+// The events describe what just happened; events for the resulting position are appended.
+func CreateCompleteGameState(gameBoard *board.Board, events []GameEvent, stockfishEngine *uci.Engine) GameState {
 	capturedWhite, capturedBlack := GetCapturedPieces(gameBoard)
 
 	// Ensure arrays are never nil
@@ -110,34 +217,36 @@ func CreateCompleteGameState(gameBoard *board.Board, message string, evaluation
 		}
 	}
 
+	// Every endpoint reports the evaluation from the same source and perspective
+	evaluation, evaluationDepth, hasEvaluation := EvaluatePosition(gameBoard, stockfishEngine)
+
+	// Add check/checkmate/draw/turn announcements for the resulting position
+	outcome := DeriveOutcome(gameBoard)
+	allEvents := append(append([]GameEvent{}, events...), outcomeEvents(outcome, gameBoard.SideToMove())...)
+
 	state := GameState{
 		Board:            gameBoard,
-		Message:          message,
+		Message:          FormatMessage(allEvents),
+		Events:           allEvents,
 		Evaluation:       evaluation,
+		EvaluationDepth:  evaluationDepth,
+		HasEvaluation:    hasEvaluation,
 		CapturedWhite:    capturedWhite,
 		CapturedBlack:    capturedBlack,
 		StockfishVersion: stockfishVersion,
 	}
+	state.Opening, state.ECO = opening.Identify(gameBoard.UCIMoves)
+	state.CanCastle = castlingAvailability(gameBoard)
 
-	// Update check/checkmate status
-	state.InCheck = gameBoard.IsInCheck(gameBoard.WhiteToMove)
-	state.IsCheckmate = gameBoard.IsCheckmate(gameBoard.WhiteToMove)
-	state.GameOver = state.IsCheckmate
-
-	// Check for draws
-	isDraw := gameBoard.IsDraw()
-	drawReason := ""
-	if isDraw {
-		if gameBoard.IsThreefoldRepetition() {
-			drawReason = "Threefold repetition"
-		} else {
-			drawReason = "Stalemate"
+	// Check, checkmate and draw status all come from the same derivation
+	state.applyOutcome(outcome)
+	AuditState(&state)
+	if state.GameOver {
+		if stats, err := Stats(gameBoard); err == nil {
+			state.Stats = &stats
 		}
 	}
-	state.Draw = isDraw
-	state.DrawReason = drawReason
-	state.GameOver = state.IsCheckmate || isDraw
-	state.ThreefoldRep = gameBoard.IsThreefoldRepetition()
+	state.FiftyMoveCounter = gameBoard.FiftyMoveCounter()
 	if gameBoard.PositionHistory != nil {
 		for _, count := range gameBoard.PositionHistory {
 			if count > state.PositionCount {
@@ -146,28 +255,5 @@ func CreateCompleteGameState(gameBoard *board.Board, message string, evaluation
 		}
 	}
 
-	// Enhance message with check/checkmate announcements
-	if state.IsCheckmate {
-		if gameBoard.WhiteToMove {
-			state.Message = "Checkmate! Black wins!"
-		} else {
-			state.Message = "Checkmate! White wins!"
-		}
-	} else if state.InCheck {
-		if gameBoard.WhiteToMove {
-			state.Message = "White is in check!"
-		} else {
-			state.Message = "Black is in check!"
-		}
-	} else if state.Draw {
-		state.Message = fmt.Sprintf("Draw! %s", drawReason)
-	} else if message == "" {
-		if gameBoard.WhiteToMove {
-			state.Message = "White to move"
-		} else {
-			state.Message = "Black to move"
-		}
-	}
-
 	return state
 } 
\ No newline at end of file
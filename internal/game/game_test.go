@@ -0,0 +1,102 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/uci"
+)
+
+func TestChooseLineWithContempt(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []uci.MultiPVLine
+		contempt int
+		want     string
+	}{
+		{
+			name: "faster mate wins",
+			lines: []uci.MultiPVLine{
+				{PV: []string{"a2a3"}, Mate: 4, Score: 900},
+				{PV: []string{"b2b3"}, Mate: 2, Score: 900},
+				{PV: []string{"c2c3"}, Score: 5000},
+			},
+			want: "b2b3",
+		},
+		{
+			name: "slower mate against is better",
+			lines: []uci.MultiPVLine{
+				{PV: []string{"a2a3"}, Mate: -1},
+				{PV: []string{"b2b3"}, Mate: -5},
+			},
+			want: "b2b3",
+		},
+		{
+			name: "contempt avoids the draw",
+			lines: []uci.MultiPVLine{
+				{PV: []string{"a2a3"}, Score: 0},
+				{PV: []string{"b2b3"}, Score: -20},
+			},
+			contempt: 50,
+			want:     "b2b3",
+		},
+		{
+			name: "draw is taken without contempt",
+			lines: []uci.MultiPVLine{
+				{PV: []string{"a2a3"}, Score: -20},
+				{PV: []string{"b2b3"}, Score: 0},
+			},
+			want: "b2b3",
+		},
+		{
+			name: "lines without a move are skipped",
+			lines: []uci.MultiPVLine{
+				{Mate: 1},
+				{PV: []string{"b2b3"}, Score: -300},
+			},
+			want: "b2b3",
+		},
+	}
+
+	for _, tt := range tests {
+		line, ok := ChooseLineWithContempt(tt.lines, tt.contempt)
+		if !ok {
+			t.Errorf("%s: no line chosen", tt.name)
+			continue
+		}
+		if line.PV[0] != tt.want {
+			t.Errorf("%s: chose %s, want %s", tt.name, line.PV[0], tt.want)
+		}
+	}
+
+	if _, ok := ChooseLineWithContempt(nil, 0); ok {
+		t.Error("chose a line from no lines")
+	}
+}
+
+func TestCapturedPiecesAfterPromotion(t *testing.T) {
+	// 1.axb8=Q+ Rxb8: the pawn takes a knight as it promotes, and the new queen is taken back
+	gameBoard, err := board.FromFEN("rn2k3/P7/8/8/8/8/8/4K3 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gameBoard.ApplyMoves([]string{"a7b8q", "a8b8"}); err != nil {
+		t.Fatal(err)
+	}
+
+	capturedWhite, capturedBlack := GetCapturedPieces(gameBoard)
+	if want := []CapturedPiece{{Type: "N", Value: 3}}; !reflect.DeepEqual(capturedWhite, want) {
+		t.Errorf("captured by White = %v, want %v", capturedWhite, want)
+	}
+	// Black took a queen, not the pawn that became it
+	if want := []CapturedPiece{{Type: "Q", Value: 9}}; !reflect.DeepEqual(capturedBlack, want) {
+		t.Errorf("captured by Black = %v, want %v", capturedBlack, want)
+	}
+
+	state := CreateCompleteGameState(gameBoard, nil, nil)
+	if !reflect.DeepEqual(state.CapturedWhite, capturedWhite) || !reflect.DeepEqual(state.CapturedBlack, capturedBlack) {
+		t.Errorf("game state captures = %v and %v, want %v and %v",
+			state.CapturedWhite, state.CapturedBlack, capturedWhite, capturedBlack)
+	}
+}
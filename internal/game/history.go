@@ -0,0 +1,110 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// Evaluation swings (in centipawns, from the mover's perspective) used to classify moves
+const (
+	BlunderThreshold   = -200
+	MistakeThreshold   = -100
+	BrilliantThreshold = 100
+)
+
+// HistoryMove represents one half-move in the game history with its evaluation
+type HistoryMove struct {
+	SAN         string `json:"san"`
+	UCI         string `json:"uci"`
+	Eval        *int   `json:"eval,omitempty"` // Evaluation after the move from White's perspective
+	IsMistake   bool   `json:"isMistake"`
+	IsBlunder   bool   `json:"isBlunder"`
+	IsBrilliant bool   `json:"isBrilliant"`
+}
+
+// HistoryEntry represents a full move (White's and Black's half-moves)
+type HistoryEntry struct {
+	MoveNumber int          `json:"moveNumber"`
+	White      *HistoryMove `json:"white,omitempty"`
+	Black      *HistoryMove `json:"black,omitempty"`
+}
+
+// PositionEvaluator returns a White-perspective evaluation of a position, ok is false if unavailable
+type PositionEvaluator func(b *board.Board) (evaluation int, ok bool)
+
+// HistoryProgress is called after each half-move is processed with the number done and the total
+type HistoryProgress func(done, total int)
+
+// BuildHistory replays the game and returns each move with its evaluation and classification
+// evaluate may be nil, in which case moves are returned without evaluations
+func BuildHistory(gameBoard *board.Board, evaluate PositionEvaluator) ([]HistoryEntry, error) {
+	return BuildHistoryContext(context.Background(), gameBoard, evaluate, nil)
+}
+
+// BuildHistoryContext is BuildHistory for long analyses: it stops when ctx is cancelled
+// and reports its progress after every half-move (progress may be nil)
+func BuildHistoryContext(ctx context.Context, gameBoard *board.Board, evaluate PositionEvaluator, progress HistoryProgress) ([]HistoryEntry, error) {
+	replay := board.NewBoard()
+
+	var prevEval int
+	prevOK := false
+	if evaluate != nil {
+		prevEval, prevOK = evaluate(replay)
+	}
+
+	entries := []HistoryEntry{}
+	for i, uciMove := range gameBoard.UCIMoves {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		isWhite := replay.WhiteToMove
+		moveNumber := i/2 + 1
+
+		if err := replay.MakeUCIMove(uciMove); err != nil {
+			return nil, fmt.Errorf("failed to replay move %d (%s): %v", i+1, uciMove, err)
+		}
+
+		halfMove := &HistoryMove{UCI: uciMove, SAN: uciMove}
+		if i < len(gameBoard.MovesPlayed) {
+			halfMove.SAN = gameBoard.MovesPlayed[i]
+		}
+
+		if evaluate != nil {
+			eval, ok := evaluate(replay)
+			if ok {
+				halfMove.Eval = &eval
+
+				// Classify by how much the move changed the evaluation for the mover
+				if prevOK {
+					delta := eval - prevEval
+					if !isWhite {
+						delta = -delta
+					}
+					halfMove.IsBlunder = delta <= BlunderThreshold
+					halfMove.IsMistake = delta <= MistakeThreshold && !halfMove.IsBlunder
+					halfMove.IsBrilliant = delta >= BrilliantThreshold
+				}
+			}
+			prevEval, prevOK = eval, ok
+		}
+
+		// Start a new entry for White's moves (or if the game starts with Black to move)
+		if isWhite || len(entries) == 0 {
+			entries = append(entries, HistoryEntry{MoveNumber: moveNumber})
+		}
+		if isWhite {
+			entries[len(entries)-1].White = halfMove
+		} else {
+			entries[len(entries)-1].Black = halfMove
+		}
+
+		if progress != nil {
+			progress(i+1, len(gameBoard.UCIMoves))
+		}
+	}
+
+	return entries, nil
+}
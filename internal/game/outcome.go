@@ -0,0 +1,160 @@
+package game
+
+import (
+	"log/slog"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// Game results, in PGN notation
+const (
+	ResultWhiteWins = "1-0"
+	ResultBlackWins = "0-1"
+	ResultDraw      = "1/2-1/2"
+	ResultOngoing   = "*"
+)
+
+// Draw reasons reported in GameState.DrawReason
+const (
+	DrawReasonStalemate   = "Stalemate"
+	DrawReasonThreefold   = "Threefold repetition"
+	DrawReasonFiftyMove   = "Fifty-move rule"
+	DrawReasonTheoretical = "Theoretical draw"
+)
+
+// Outcome is the status of a position - the single source for the status fields of GameState
+type Outcome struct {
+	Result       string
+	InCheck      bool
+	IsCheckmate  bool
+	Draw         bool
+	DrawReason   string
+	ThreefoldRep bool
+	LegalMoves   int // Number of legal moves for the side to move
+
+	// DrawishEndgame marks material that can't force mate against correct defense, though
+	// mate is still possible, so the game goes on (two knights, opposite-colored bishops)
+	DrawishEndgame bool
+}
+
+// DeriveOutcome determines whether the game is over, and how, from the position
+func DeriveOutcome(gameBoard *board.Board) Outcome {
+	// Move generation is the expensive part, so the legal moves are generated once for every check
+	legalMoves := gameBoard.GetLegalMoves()
+
+	toMove := gameBoard.SideToMove()
+	outcome := Outcome{
+		Result:       ResultOngoing,
+		InCheck:      gameBoard.IsInCheck(toMove),
+		ThreefoldRep: gameBoard.IsThreefoldRepetition(),
+		LegalMoves:   len(legalMoves),
+
+		DrawishEndgame: gameBoard.IsDrawishEndgame(),
+	}
+
+	switch {
+	case outcome.InCheck && outcome.LegalMoves == 0:
+		outcome.IsCheckmate = true
+		outcome.Result = ResultWhiteWins
+		if toMove == board.White {
+			outcome.Result = ResultBlackWins
+		}
+	case gameBoard.IsDrawWithLegalMoves(legalMoves):
+		outcome.Draw = true
+		outcome.Result = ResultDraw
+		switch {
+		case outcome.ThreefoldRep:
+			outcome.DrawReason = DrawReasonThreefold
+		case outcome.LegalMoves == 0:
+			outcome.DrawReason = DrawReasonStalemate
+		case gameBoard.IsTheoreticalDraw():
+			outcome.DrawReason = DrawReasonTheoretical
+		default:
+			outcome.DrawReason = DrawReasonFiftyMove
+		}
+	}
+
+	return outcome
+}
+
+// applyOutcome sets the status fields of the state from the outcome
+func (s *GameState) applyOutcome(outcome Outcome) {
+	s.Result = outcome.Result
+	s.GameOver = outcome.Result != ResultOngoing
+	s.InCheck = outcome.InCheck
+	s.IsCheckmate = outcome.IsCheckmate
+	s.Draw = outcome.Draw
+	s.DrawReason = outcome.DrawReason
+	s.ThreefoldRep = outcome.ThreefoldRep
+	s.LegalMoveCount = outcome.LegalMoves
+	s.DrawishEndgame = outcome.DrawishEndgame
+}
+
+// StatusViolations lists the ways the status fields of a state contradict each other
+func StatusViolations(state GameState) []string {
+	var violations []string
+
+	if state.GameOver != (state.Result != ResultOngoing) {
+		violations = append(violations, "gameOver disagrees with result")
+	}
+	timedOut := state.Clock != nil && (state.Clock.WhiteTimedOut || state.Clock.BlackTimedOut)
+	if state.GameOver && !state.IsCheckmate && !state.Draw && !timedOut {
+		violations = append(violations, "game over without checkmate, draw or timeout")
+	}
+	if state.Draw && (state.DrawReason == "" || state.Result != ResultDraw) {
+		violations = append(violations, "draw without a reason or a drawn result")
+	}
+	if state.IsCheckmate && (!state.InCheck || (state.Result != ResultWhiteWins && state.Result != ResultBlackWins)) {
+		violations = append(violations, "checkmate without check or a decisive result")
+	}
+	if state.IsCheckmate && state.Draw {
+		violations = append(violations, "both checkmate and draw")
+	}
+
+	// The move generator and the checkmate/stalemate detection must agree
+	if state.LegalMoveCount == 0 && !state.IsCheckmate && !state.Draw {
+		violations = append(violations, "no legal moves but neither checkmate nor draw")
+	}
+	if state.LegalMoveCount > 0 && state.IsCheckmate {
+		violations = append(violations, "checkmate with legal moves available")
+	}
+
+	return violations
+}
+
+// AuditState checks the status invariants of a state; on any violation it logs them and
+// re-derives the status from the board. It returns the violations found.
+func AuditState(state *GameState) []string {
+	violations := StatusViolations(*state)
+	if len(violations) > 0 && state.Board != nil {
+		slog.Error("inconsistent game state corrected", "fen", state.Board.ToFEN(), "violations", violations)
+		state.applyOutcome(DeriveOutcome(state.Board))
+	}
+	return violations
+}
+
+// CastlingAvailability tells which castling moves are legal right now for each side
+type CastlingAvailability struct {
+	WhiteKingside  bool `json:"whiteKingside"`
+	WhiteQueenside bool `json:"whiteQueenside"`
+	BlackKingside  bool `json:"blackKingside"`
+	BlackQueenside bool `json:"blackQueenside"`
+}
+
+// castlingAvailability checks every castling move on the board
+func castlingAvailability(gameBoard *board.Board) CastlingAvailability {
+	return CastlingAvailability{
+		WhiteKingside:  gameBoard.CanCastleKingside(board.White),
+		WhiteQueenside: gameBoard.CanCastleQueenside(board.White),
+		BlackKingside:  gameBoard.CanCastleKingside(board.Black),
+		BlackQueenside: gameBoard.CanCastleQueenside(board.Black),
+	}
+}
+
+// ErrorState builds a game state reporting an error, without consulting the engine
+func ErrorState(gameBoard *board.Board, message string) GameState {
+	state := GameState{Board: gameBoard, Error: message}
+	state.applyOutcome(DeriveOutcome(gameBoard))
+	AuditState(&state)
+	return state
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+func TestStatusInvariantsOverRandomPlayouts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	endings := make(map[string]int)
+
+	for game := 0; game < 30; game++ {
+		b := board.NewBoard()
+		for ply := 0; ; ply++ {
+			// The derivation itself must be consistent, before any correction by the audit
+			var derived GameState
+			derived.applyOutcome(DeriveOutcome(b))
+			if violations := StatusViolations(derived); len(violations) > 0 {
+				t.Fatalf("game %d ply %d: derived status of %s violates %v", game, ply, b.ToFEN(), violations)
+			}
+
+			// And so must every state the builders emit
+			for name, state := range map[string]GameState{
+				"complete": CreateCompleteGameState(b, nil, nil),
+				"error":    ErrorState(b, "test"),
+			} {
+				if violations := StatusViolations(state); len(violations) > 0 {
+					t.Fatalf("game %d ply %d: %s state of %s violates %v", game, ply, name, b.ToFEN(), violations)
+				}
+			}
+
+			if derived.GameOver || ply == 400 {
+				endings[derived.Result+" "+derived.DrawReason]++
+				break
+			}
+			legalMoves := b.GetLegalMoves()
+			if err := b.MakeUCIMove(legalMoves[rng.Intn(len(legalMoves))]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	t.Logf("endings: %v", endings)
+}
+
+func TestAuditStateCorrectsViolations(t *testing.T) {
+	b := board.NewBoard()
+	for _, move := range []string{"f2f3", "e7e5", "g2g4", "d8h4"} { // Fool's mate
+		if err := b.MakeUCIMove(move); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A state claiming the game is over without saying how
+	state := GameState{Board: b, GameOver: true, Result: ResultOngoing}
+	if violations := AuditState(&state); len(violations) == 0 {
+		t.Fatal("no violations reported")
+	}
+	if violations := StatusViolations(state); len(violations) > 0 {
+		t.Errorf("audited state still violates %v", violations)
+	}
+	if !state.IsCheckmate || !state.InCheck || state.Result != ResultBlackWins || !state.GameOver {
+		t.Errorf("audited state = checkmate %v, check %v, result %s, game over %v; want Black to have mated",
+			state.IsCheckmate, state.InCheck, state.Result, state.GameOver)
+	}
+}
+
+func TestDrawishEndgameIsNotGameOver(t *testing.T) {
+	for _, fen := range []string{
+		"8/8/4k3/8/8/3K4/5N2/6N1 w - - 0 1", // Two knights
+		"8/8/4k3/1b6/8/3K4/8/2B5 w - - 0 1", // Opposite-colored bishops
+	} {
+		b, err := board.FromFEN(fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := CreateCompleteGameState(b, nil, nil)
+		if state.GameOver || state.Draw || state.Result != ResultOngoing {
+			t.Errorf("%s: game over %v, draw %v, result %s; want the game to go on", fen, state.GameOver, state.Draw, state.Result)
+		}
+		if !state.DrawishEndgame {
+			t.Errorf("%s: drawishEndgame not set", fen)
+		}
+	}
+
+	// A lone bishop can never mate, so that game is over
+	b, err := board.FromFEN("8/8/4k3/8/8/3K4/8/2B5 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := CreateCompleteGameState(b, nil, nil)
+	if !state.GameOver || state.DrawReason != DrawReasonTheoretical || state.DrawishEndgame {
+		t.Errorf("lone bishop: game over %v, reason %q, drawish %v; want a theoretical draw",
+			state.GameOver, state.DrawReason, state.DrawishEndgame)
+	}
+}
@@ -0,0 +1,36 @@
+package game
+
+import (
+	"context"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// PVSeriesPlies is how many plies of a principal variation PVEvalSeries evaluates
+const PVSeriesPlies = 6
+
+// PVEvalSeries plays a principal variation on a copy of start and evaluates the position
+// after each of its first PVSeriesPlies moves, from White's perspective. It stops early
+// (returning the evaluations so far) when ctx is done, a move can't be played or an
+// evaluation fails.
+func PVEvalSeries(ctx context.Context, start *board.Board, pv []string, evaluate PositionEvaluator) []int {
+	series := []int{}
+	replay := start.Clone()
+
+	for i, uciMove := range pv {
+		if i == PVSeriesPlies || ctx.Err() != nil {
+			break
+		}
+		if err := replay.MakeUCIMove(uciMove); err != nil {
+			break
+		}
+
+		eval, ok := evaluate(replay)
+		if !ok {
+			break
+		}
+		series = append(series, eval)
+	}
+
+	return series
+}
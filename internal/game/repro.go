@@ -0,0 +1,77 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// ReproMove is one half-move of a reproducibility bundle
+type ReproMove struct {
+	UCI    string        `json:"uci"`
+	Engine *EngineConfig `json:"engine,omitempty"` // Settings the engine chose the move with; nil for a player's move
+}
+
+// ReproBundle holds what is needed to replay the engine's decisions in a game
+type ReproBundle struct {
+	StartFEN      string      `json:"startFen"`
+	EngineVersion string      `json:"engineVersion,omitempty"` // Engine that played the game, as reported over UCI
+	Threads       int         `json:"threads,omitempty"`       // Search threads the engine played with (0 = engine default)
+	Moves         []ReproMove `json:"moves"`
+}
+
+// ReplayDivergence is the first engine move of a replay that differs from the recorded one
+type ReplayDivergence struct {
+	Ply      int    `json:"ply"` // Half-move index, from 0
+	FEN      string `json:"fen"` // Position the engine was asked about
+	Expected string `json:"expected"`
+	Got      string `json:"got"`
+}
+
+// MoveChooser asks an engine for its move in a position with the given settings, in UCI notation
+type MoveChooser func(b *board.Board, settings EngineConfig) (string, error)
+
+// NewReproBundle builds the bundle of a game started from the initial position. engineMoves
+// holds the settings of each engine move by half-move index.
+func NewReproBundle(gameBoard *board.Board, engineMoves map[int]EngineConfig, engineVersion string, threads int) ReproBundle {
+	bundle := ReproBundle{
+		StartFEN:      board.NewBoard().ToFEN(),
+		EngineVersion: engineVersion,
+		Threads:       threads,
+		Moves:         []ReproMove{},
+	}
+	for ply, uciMove := range gameBoard.UCIMoves {
+		move := ReproMove{UCI: uciMove}
+		if settings, ok := engineMoves[ply]; ok {
+			move.Engine = &settings
+		}
+		bundle.Moves = append(bundle.Moves, move)
+	}
+	return bundle
+}
+
+// ReplayBundle plays the bundle's moves, asking choose for every engine move, and returns the
+// first one where the engine decides differently (nil if the replay matches)
+func ReplayBundle(bundle ReproBundle, choose MoveChooser) (*ReplayDivergence, error) {
+	replay, err := board.FromFEN(bundle.StartFEN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start position: %v", err)
+	}
+
+	for ply, move := range bundle.Moves {
+		if move.Engine != nil {
+			got, err := choose(replay, *move.Engine)
+			if err != nil {
+				return nil, fmt.Errorf("engine failed at half-move %d: %v", ply+1, err)
+			}
+			if got != move.UCI {
+				return &ReplayDivergence{Ply: ply, FEN: replay.ToFEN(), Expected: move.UCI, Got: got}, nil
+			}
+		}
+
+		if err := replay.MakeUCIMove(move.UCI); err != nil {
+			return nil, fmt.Errorf("failed to replay move %d (%s): %v", ply+1, move.UCI, err)
+		}
+	}
+	return nil, nil
+}
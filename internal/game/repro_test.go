@@ -0,0 +1,105 @@
+package game
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// firstLegalMove is a deterministic stand-in engine: it plays the first legal move in sort order
+func firstLegalMove(b *board.Board, settings EngineConfig) (string, error) {
+	legalMoves := b.GetLegalMoves()
+	sort.Strings(legalMoves)
+	return legalMoves[0], nil
+}
+
+// playRecordedGame plays plies half-moves, the engine moving for Black, and returns the
+// game with the settings of each engine move
+func playRecordedGame(t *testing.T, plies int) (*board.Board, map[int]EngineConfig) {
+	t.Helper()
+	gameBoard := board.NewBoard()
+	engineMoves := make(map[int]EngineConfig)
+	settings := DefaultEngineConfig()
+
+	for ply := 0; ply < plies; ply++ {
+		var move string
+		if gameBoard.WhiteToMove {
+			legalMoves := gameBoard.GetLegalMoves()
+			sort.Strings(legalMoves)
+			move = legalMoves[len(legalMoves)-1]
+		} else {
+			move, _ = firstLegalMove(gameBoard, settings)
+			engineMoves[ply] = settings
+		}
+		if err := gameBoard.MakeUCIMove(move); err != nil {
+			t.Fatalf("ply %d: %v", ply, err)
+		}
+	}
+	return gameBoard, engineMoves
+}
+
+func TestReplayBundleNoDivergence(t *testing.T) {
+	gameBoard, engineMoves := playRecordedGame(t, 12)
+	bundle := NewReproBundle(gameBoard, engineMoves, "test engine", 4)
+
+	// The bundle must survive the JSON round trip of the download
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var downloaded ReproBundle
+	if err := json.Unmarshal(data, &downloaded); err != nil {
+		t.Fatal(err)
+	}
+
+	if downloaded.Threads != 4 {
+		t.Errorf("bundle records %d threads, want 4", downloaded.Threads)
+	}
+	if len(downloaded.Moves) != 12 {
+		t.Fatalf("bundle has %d moves, want 12", len(downloaded.Moves))
+	}
+	for ply, move := range downloaded.Moves {
+		if isEngineMove := ply%2 == 1; (move.Engine != nil) != isEngineMove {
+			t.Errorf("ply %d: engine settings recorded = %v, want %v", ply, move.Engine != nil, isEngineMove)
+		}
+	}
+
+	divergence, err := ReplayBundle(downloaded, firstLegalMove)
+	if err != nil {
+		t.Fatalf("ReplayBundle: %v", err)
+	}
+	if divergence != nil {
+		t.Errorf("unexpected divergence: %+v", divergence)
+	}
+}
+
+func TestReplayBundleReportsFirstDivergence(t *testing.T) {
+	gameBoard, engineMoves := playRecordedGame(t, 8)
+	bundle := NewReproBundle(gameBoard, engineMoves, "", 0)
+
+	// An engine that changes its mind from the third engine move on
+	calls := 0
+	divergence, err := ReplayBundle(bundle, func(b *board.Board, settings EngineConfig) (string, error) {
+		calls++
+		legalMoves := b.GetLegalMoves()
+		sort.Strings(legalMoves)
+		if calls >= 3 {
+			return legalMoves[len(legalMoves)-1], nil
+		}
+		return legalMoves[0], nil
+	})
+	if err != nil {
+		t.Fatalf("ReplayBundle: %v", err)
+	}
+	if divergence == nil {
+		t.Fatal("expected a divergence")
+	}
+	if divergence.Ply != 5 {
+		t.Errorf("divergence at ply %d, want 5", divergence.Ply)
+	}
+	if divergence.Expected != bundle.Moves[5].UCI || divergence.Got == divergence.Expected {
+		t.Errorf("divergence = %+v", divergence)
+	}
+}
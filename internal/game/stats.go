@@ -0,0 +1,112 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// SideStats holds the statistics for one side of a game
+type SideStats struct {
+	Moves           int            `json:"moves"`
+	Captures        int            `json:"captures"`
+	Checks          int            `json:"checks"`
+	CastlingPly     int            `json:"castlingPly"`     // Ply on which the side castled (0 if it didn't)
+	MaterialWon     int            `json:"materialWon"`     // Point value of the pieces captured
+	PieceMoveCounts map[string]int `json:"pieceMoveCounts"` // Moves per piece type (P, N, B, R, Q, K)
+	MostMovedPiece  string         `json:"mostMovedPiece"`  // Piece that moved most, by type and starting square (e.g. "Ng1")
+	MostMovedCount  int            `json:"mostMovedCount"`
+}
+
+// GameStats summarizes a game for the end-of-game screen
+type GameStats struct {
+	Plies             int       `json:"plies"`
+	White             SideStats `json:"white"`
+	Black             SideStats `json:"black"`
+	MaterialExchanged int       `json:"materialExchanged"` // Total point value of all captured pieces
+}
+
+// Stats replays the game's move history and aggregates per-side statistics
+func Stats(gameBoard *board.Board) (GameStats, error) {
+	replay := board.NewBoard()
+	stats := GameStats{
+		White: SideStats{PieceMoveCounts: map[string]int{}},
+		Black: SideStats{PieceMoveCounts: map[string]int{}},
+	}
+
+	// Follow each piece by the square it started the game on
+	origin := map[string]string{}
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			if replay.GetPiece(rank, file) != board.Empty {
+				square := board.GetSquareName(rank, file)
+				origin[square] = square
+			}
+		}
+	}
+	pieceMoves := map[string]int{}
+
+	for i, uciMove := range gameBoard.UCIMoves {
+		side := &stats.White
+		if !replay.WhiteToMove {
+			side = &stats.Black
+		}
+
+		from, to := uciMove[0:2], uciMove[2:4]
+		fromRank, fromFile := board.GetSquareCoords(from)
+		toRank, toFile := board.GetSquareCoords(to)
+		piece := replay.GetPiece(fromRank, fromFile)
+		captured := replay.GetPiece(toRank, toFile)
+		pieceType := board.GetPieceType(piece)
+
+		// A pawn moving diagonally onto an empty square captures en passant
+		if pieceType == "P" && fromFile != toFile && captured == board.Empty {
+			captured = board.WP
+		}
+
+		if err := replay.MakeUCIMove(uciMove); err != nil {
+			return GameStats{}, fmt.Errorf("failed to replay move %d (%s): %v", i+1, uciMove, err)
+		}
+
+		side.Moves++
+		side.PieceMoveCounts[pieceType]++
+		if captured != board.Empty {
+			side.Captures++
+			side.MaterialWon += board.GetPieceValue(captured)
+			stats.MaterialExchanged += board.GetPieceValue(captured)
+		}
+		if replay.IsInCheck(replay.SideToMove()) {
+			side.Checks++
+		}
+
+		// Track the moving piece; castling also moves the rook
+		id := origin[from]
+		delete(origin, from)
+		origin[to] = id
+		pieceMoves[pieceType+id]++
+		if pieceType == "K" && fromFile == 4 && (toFile == 6 || toFile == 2) {
+			side.CastlingPly = i + 1
+			rookFrom, rookTo := "h"+from[1:], "f"+from[1:]
+			if toFile == 2 {
+				rookFrom, rookTo = "a"+from[1:], "d"+from[1:]
+			}
+			origin[rookTo] = origin[rookFrom]
+			delete(origin, rookFrom)
+		}
+	}
+	stats.Plies = len(gameBoard.UCIMoves)
+
+	// Pick each side's most moved piece (ties go to the alphabetically first for stable output)
+	for key, count := range pieceMoves {
+		side := &stats.White
+		if key[2] == '7' || key[2] == '8' {
+			side = &stats.Black
+		}
+		if count > side.MostMovedCount || (count == side.MostMovedCount && key < side.MostMovedPiece) {
+			side.MostMovedPiece = key
+			side.MostMovedCount = count
+		}
+	}
+
+	return stats, nil
+}
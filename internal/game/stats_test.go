@@ -0,0 +1,66 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+func TestStats(t *testing.T) {
+	// 1.e4 d5 2.e5 f5 3.exf6 e.p. Nc6 4.fxg7 Qd6 5.gxh8=Q Be6 6.Nf3 O-O-O 7.Be2 Kb8
+	// 8.O-O Qxh2+ 9.Kxh2: en passant, a promotion with capture, castling on both wings and a check
+	gameBoard := board.NewBoard()
+	if _, err := gameBoard.ApplyMoves([]string{
+		"e2e4", "d7d5", "e4e5", "f7f5", "e5f6", "b8c6", "f6g7", "d8d6", "g7h8q",
+		"c8e6", "g1f3", "e8c8", "f1e2", "c8b8", "e1g1", "d6h2", "g1h2",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := Stats(gameBoard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := GameStats{
+		Plies: 17,
+		White: SideStats{
+			Moves:           9,
+			Captures:        4, // exf6 e.p., fxg7, gxh8=Q, Kxh2
+			Checks:          0,
+			CastlingPly:     15,
+			MaterialWon:     1 + 1 + 5 + 9,
+			PieceMoveCounts: map[string]int{"P": 5, "N": 1, "B": 1, "K": 2},
+			MostMovedPiece:  "Pe2", // The e-pawn that went on to promote
+			MostMovedCount:  5,
+		},
+		Black: SideStats{
+			Moves:           8,
+			Captures:        1,
+			Checks:          1,
+			CastlingPly:     12,
+			MaterialWon:     1,
+			PieceMoveCounts: map[string]int{"P": 2, "N": 1, "Q": 2, "B": 1, "K": 2},
+			MostMovedPiece:  "Ke8", // Tied with the queen, and first alphabetically
+			MostMovedCount:  2,
+		},
+		MaterialExchanged: 17,
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("Stats =\n%+v, want\n%+v", stats, want)
+	}
+}
+
+func TestStatsEmptyGame(t *testing.T) {
+	stats, err := Stats(board.NewBoard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Plies != 0 || stats.White.Moves != 0 || stats.Black.Moves != 0 || stats.MaterialExchanged != 0 {
+		t.Errorf("Stats of an empty game = %+v", stats)
+	}
+	if stats.White.MostMovedPiece != "" || stats.White.PieceMoveCounts == nil {
+		t.Errorf("white stats of an empty game = %+v", stats.White)
+	}
+}
@@ -0,0 +1,154 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/uci"
+)
+
+// DefaultMaxMoves is the number of full moves after which PlayTournament adjudicates a game as drawn
+const DefaultMaxMoves = 200
+
+// PlayerFunc chooses a move in UCI notation for the side to move
+type PlayerFunc func(b *board.Board) (string, error)
+
+// GameResult is the outcome of one automated game
+type GameResult struct {
+	Result   string   `json:"result"` // "1-0", "0-1" or "1/2-1/2"
+	Reason   string   `json:"reason"` // Checkmate, Stalemate, Threefold repetition, ...
+	UCIMoves []string `json:"uciMoves"`
+	Plies    int      `json:"plies"`
+}
+
+// TournamentResult is the score of the first player across a match
+type TournamentResult struct {
+	Wins       int          `json:"wins"`
+	Draws      int          `json:"draws"`
+	Losses     int          `json:"losses"`
+	TotalMoves int          `json:"totalMoves"` // Total plies played across all games
+	Games      []GameResult `json:"games"`
+}
+
+// Score returns the first player's score as a fraction (wins count 1, draws 1/2)
+func (t TournamentResult) Score() float64 {
+	games := t.Wins + t.Draws + t.Losses
+	if games == 0 {
+		return 0
+	}
+	return (float64(t.Wins) + float64(t.Draws)/2) / float64(games)
+}
+
+// StockfishPlayer plays the engine's best move at the given depth
+func StockfishPlayer(engine *uci.Engine, depth int) PlayerFunc {
+	return func(b *board.Board) (string, error) {
+		engineMove, err := engine.GetBestMove(b.ToFEN(), depth)
+		if err != nil {
+			return "", err
+		}
+		if engineMove == nil {
+			return "", fmt.Errorf("no move received from engine")
+		}
+		return engineMove.UCI, nil
+	}
+}
+
+// RandomPlayer plays a uniformly random legal move
+func RandomPlayer() PlayerFunc {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return func(b *board.Board) (string, error) {
+		legalMoves := b.GetLegalMoves()
+		if len(legalMoves) == 0 {
+			return "", fmt.Errorf("no legal moves")
+		}
+		return legalMoves[rng.Intn(len(legalMoves))], nil
+	}
+}
+
+// PlayGame plays one game from the starting position. A player that fails to return
+// a legal move loses; the game is drawn after maxMoves full moves.
+func PlayGame(white, black PlayerFunc, maxMoves int) GameResult {
+	b := board.NewBoard()
+
+	for {
+		// The result if the side to move is mated or fails to move
+		lost := "0-1"
+		if !b.WhiteToMove {
+			lost = "1-0"
+		}
+
+		if len(b.GetLegalMoves()) == 0 {
+			if b.IsInCheck(b.SideToMove()) {
+				return finishGame(b, lost, "Checkmate")
+			}
+			return finishGame(b, "1/2-1/2", DrawReasonStalemate)
+		}
+		if b.IsThreefoldRepetition() {
+			return finishGame(b, "1/2-1/2", DrawReasonThreefold)
+		}
+		if b.IsFiftyMoveRule() {
+			return finishGame(b, "1/2-1/2", DrawReasonFiftyMove)
+		}
+		if b.IsTheoreticalDraw() {
+			return finishGame(b, "1/2-1/2", DrawReasonTheoretical)
+		}
+		if len(b.UCIMoves) >= 2*maxMoves {
+			return finishGame(b, "1/2-1/2", "Move limit")
+		}
+
+		player := white
+		if !b.WhiteToMove {
+			player = black
+		}
+
+		uciMove, err := player(b.Clone())
+		if err != nil {
+			return finishGame(b, lost, fmt.Sprintf("Player error: %v", err))
+		}
+		if err := b.MakeUCIMove(uciMove); err != nil {
+			return finishGame(b, lost, fmt.Sprintf("Illegal move %s: %v", uciMove, err))
+		}
+	}
+}
+
+// finishGame builds the result of a finished game
+func finishGame(b *board.Board, result, reason string) GameResult {
+	return GameResult{
+		Result:   result,
+		Reason:   reason,
+		UCIMoves: b.UCIMoves,
+		Plies:    len(b.UCIMoves),
+	}
+}
+
+// PlayTournament plays a match between two players, alternating colors each game,
+// and reports the result from the first player's point of view
+func PlayTournament(first, second PlayerFunc, games int) TournamentResult {
+	var result TournamentResult
+
+	for i := 0; i < games; i++ {
+		firstIsWhite := i%2 == 0
+
+		var gameResult GameResult
+		if firstIsWhite {
+			gameResult = PlayGame(first, second, DefaultMaxMoves)
+		} else {
+			gameResult = PlayGame(second, first, DefaultMaxMoves)
+		}
+
+		switch {
+		case gameResult.Result == "1/2-1/2":
+			result.Draws++
+		case (gameResult.Result == "1-0") == firstIsWhite:
+			result.Wins++
+		default:
+			result.Losses++
+		}
+		result.TotalMoves += gameResult.Plies
+		result.Games = append(result.Games, gameResult)
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/uci"
+)
+
+// stockfishPath returns the Stockfish binary to test against, from CHESS_ENGINE_PATH or the PATH
+func stockfishPath(t *testing.T) string {
+	t.Helper()
+	if path := os.Getenv("CHESS_ENGINE_PATH"); path != "" {
+		return path
+	}
+	path, err := exec.LookPath("stockfish")
+	if err != nil {
+		t.Skip("Stockfish not found; set CHESS_ENGINE_PATH to run")
+	}
+	return path
+}
+
+func TestEngineVsRandom(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays full games against the engine")
+	}
+	engine, err := uci.NewEngine(stockfishPath(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	result := PlayTournament(StockfishPlayer(engine, 4), RandomPlayer(), 10)
+	if result.Score() <= 0.9 {
+		t.Errorf("engine scored %.2f against a random mover (+%d =%d -%d), want more than 0.9",
+			result.Score(), result.Wins, result.Draws, result.Losses)
+	}
+}
+
+func TestEngineLegality(t *testing.T) {
+	// Random games until 1000 moves were played, checking every position along the way
+	plies := 0
+	for plies < 1000 {
+		result := PlayGame(RandomPlayer(), RandomPlayer(), DefaultMaxMoves)
+		if result.Plies == 0 {
+			t.Fatalf("game ended without a move: %s", result.Reason)
+		}
+
+		b := board.NewBoard()
+		for i, move := range result.UCIMoves {
+			if !b.IsMoveLegal(move) {
+				t.Fatalf("ply %d: %s is not legal in %s", i+1, move, b.ToFEN())
+			}
+			if err := b.MakeUCIMove(move); err != nil {
+				t.Fatalf("ply %d: %s: %v", i+1, move, err)
+			}
+			if err := b.Validate(); err != nil {
+				t.Fatalf("ply %d: invalid position %s after %s: %v", i+1, b.ToFEN(), move, err)
+			}
+			if b.IsInCheck(b.SideToMove().Opposite()) {
+				t.Fatalf("ply %d: %s left the mover in check: %s", i+1, move, b.ToFEN())
+			}
+
+			// Every generated move must be playable
+			for _, legal := range b.GetLegalMoves() {
+				if err := b.Clone().MakeUCIMove(legal); err != nil {
+					t.Fatalf("ply %d: generated move %s rejected in %s: %v", i+1, legal, b.ToFEN(), err)
+				}
+			}
+		}
+		plies += result.Plies
+	}
+}
+
+func TestPlayTournamentAlternatesColors(t *testing.T) {
+	resign := func(b *board.Board) (string, error) { return "", errors.New("resigns") }
+
+	result := PlayTournament(RandomPlayer(), resign, 4)
+	if result.Wins != 4 || result.Draws != 0 || result.Losses != 0 {
+		t.Fatalf("result = +%d =%d -%d, want +4 =0 -0", result.Wins, result.Draws, result.Losses)
+	}
+	for i, game := range result.Games {
+		// The first player has White in even games, so a resigning Black loses those
+		want := ResultWhiteWins
+		if i%2 == 1 {
+			want = ResultBlackWins
+		}
+		if game.Result != want {
+			t.Errorf("game %d: result %s, want %s", i+1, game.Result, want)
+		}
+	}
+}
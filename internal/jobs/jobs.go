@@ -0,0 +1,215 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Status is the lifecycle state of a job
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Reporter lets a running job publish its progress (0-100) and a short message
+type Reporter func(progress int, message string)
+
+// Func is the work performed by a job. It should return promptly once ctx is cancelled.
+type Func func(ctx context.Context, report Reporter) (interface{}, error)
+
+// Job is a snapshot of a submitted job
+type Job struct {
+	ID         string      `json:"id"`
+	Status     Status      `json:"status"`
+	Progress   int         `json:"progress"`
+	Message    string      `json:"message,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+
+	cancel context.CancelFunc
+}
+
+// finished reports whether the job has reached a final state
+func (j *Job) finished() bool {
+	return j.Status == StatusDone || j.Status == StatusFailed || j.Status == StatusCancelled
+}
+
+// Manager runs jobs on a limited number of workers and keeps finished jobs for a TTL
+type Manager struct {
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	workers chan struct{}
+	ttl     time.Duration
+	now     func() time.Time // Clock for job timestamps and expiry, replaceable in tests
+}
+
+// NewManager creates a manager running at most workers jobs at once and
+// keeping finished jobs for ttl
+func NewManager(workers int, ttl time.Duration) *Manager {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Manager{
+		jobs:    make(map[string]*Job),
+		workers: make(chan struct{}, workers),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// Submit queues fn and returns the ID of the new job
+func (m *Manager) Submit(fn Func) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &Job{
+		ID:        newID(),
+		Status:    StatusQueued,
+		CreatedAt: m.now(),
+		cancel:    cancel,
+	}
+
+	m.mu.Lock()
+	m.purgeExpired()
+	m.jobs[job.ID] = job
+	m.mu.Unlock()
+
+	go m.run(ctx, job, fn)
+	return job.ID
+}
+
+// run waits for a free worker, then executes the job and records its outcome
+func (m *Manager) run(ctx context.Context, job *Job, fn Func) {
+	defer job.cancel()
+
+	select {
+	case m.workers <- struct{}{}:
+		defer func() { <-m.workers }()
+	case <-ctx.Done():
+		m.finish(job, nil, ctx.Err())
+		return
+	}
+
+	m.mu.Lock()
+	if job.finished() {
+		m.mu.Unlock()
+		return
+	}
+	job.Status = StatusRunning
+	m.mu.Unlock()
+
+	report := func(progress int, message string) {
+		if progress < 0 {
+			progress = 0
+		} else if progress > 100 {
+			progress = 100
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if !job.finished() {
+			job.Progress = progress
+			job.Message = message
+		}
+	}
+
+	result, err := fn(ctx, report)
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	m.finish(job, result, err)
+}
+
+// finish moves the job to its final state
+func (m *Manager) finish(job *Job, result interface{}, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job.finished() {
+		return
+	}
+	now := m.now()
+	job.FinishedAt = &now
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		job.Status = StatusCancelled
+	case err != nil:
+		job.Status = StatusFailed
+		job.Error = err.Error()
+	default:
+		job.Status = StatusDone
+		job.Progress = 100
+		job.Result = result
+	}
+}
+
+// Get returns a snapshot of the job with the given ID
+func (m *Manager) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.purgeExpired()
+	job, exists := m.jobs[id]
+	if !exists {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Cancel stops a queued or running job. It returns false if the job does not exist.
+// Cancelling a finished job has no effect.
+func (m *Manager) Cancel(id string) bool {
+	m.mu.Lock()
+	job, exists := m.jobs[id]
+	queued := exists && job.Status == StatusQueued
+	m.mu.Unlock()
+
+	if !exists {
+		return false
+	}
+	job.cancel()
+	if queued {
+		m.finish(job, nil, context.Canceled)
+	}
+	return true
+}
+
+// CancelAll stops every queued or running job, e.g. when the server shuts down
+func (m *Manager) CancelAll() {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.jobs))
+	for id := range m.jobs {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		m.Cancel(id)
+	}
+}
+
+// purgeExpired drops finished jobs older than the TTL; the caller must hold m.mu
+func (m *Manager) purgeExpired() {
+	cutoff := m.now().Add(-m.ttl)
+	for id, job := range m.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// newID returns a random job identifier
+func newID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for the manager
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// waitForStatus polls the job until it reaches the status or the test times out
+func waitForStatus(t *testing.T, m *Manager, id string, status Status) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, exists := m.Get(id)
+		if !exists {
+			t.Fatalf("job %s not found waiting for %s", id, status)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, job.Status, status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// blockUntilCancelled is a job that runs until it is cancelled
+func blockUntilCancelled(ctx context.Context, report Reporter) (interface{}, error) {
+	report(50, "Working")
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	m := NewManager(1, time.Hour)
+
+	id := m.Submit(blockUntilCancelled)
+	waitForStatus(t, m, id, StatusRunning)
+
+	if !m.Cancel(id) {
+		t.Fatal("Cancel reported a missing job")
+	}
+	job := waitForStatus(t, m, id, StatusCancelled)
+	if job.FinishedAt == nil {
+		t.Error("cancelled job has no FinishedAt")
+	}
+}
+
+func TestCancelQueuedJob(t *testing.T) {
+	m := NewManager(1, time.Hour)
+	defer m.CancelAll()
+
+	running := m.Submit(blockUntilCancelled)
+	waitForStatus(t, m, running, StatusRunning)
+
+	started := make(chan struct{})
+	queued := m.Submit(func(ctx context.Context, report Reporter) (interface{}, error) {
+		close(started)
+		return nil, nil
+	})
+	if job, _ := m.Get(queued); job.Status != StatusQueued {
+		t.Fatalf("second job is %s, want %s with the only worker busy", job.Status, StatusQueued)
+	}
+
+	m.Cancel(queued)
+	if job, _ := m.Get(queued); job.Status != StatusCancelled {
+		t.Errorf("cancelled queued job is %s, want %s", job.Status, StatusCancelled)
+	}
+
+	// Freeing the worker must not start the cancelled job
+	m.Cancel(running)
+	waitForStatus(t, m, running, StatusCancelled)
+	select {
+	case <-started:
+		t.Error("cancelled queued job ran")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if m.Cancel("missing") {
+		t.Error("Cancel of an unknown job reported success")
+	}
+}
+
+func TestFinishedJobsExpire(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	m := NewManager(2, time.Hour)
+	m.now = clock.Now
+
+	done := m.Submit(func(ctx context.Context, report Reporter) (interface{}, error) {
+		return "result", nil
+	})
+	job := waitForStatus(t, m, done, StatusDone)
+	if job.Result != "result" || job.Progress != 100 {
+		t.Errorf("finished job = %+v", job)
+	}
+
+	running := m.Submit(blockUntilCancelled)
+	defer m.Cancel(running)
+	waitForStatus(t, m, running, StatusRunning)
+
+	clock.Advance(59 * time.Minute)
+	if _, exists := m.Get(done); !exists {
+		t.Error("finished job expired before its TTL")
+	}
+
+	clock.Advance(2 * time.Minute)
+	if _, exists := m.Get(done); exists {
+		t.Error("finished job still present after its TTL")
+	}
+	if _, exists := m.Get(running); !exists {
+		t.Error("running job expired")
+	}
+}
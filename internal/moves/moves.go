@@ -18,17 +18,93 @@ type Move struct {
 	Checkmate bool   // Whether the move gives checkmate
 }
 
+// ToUCI returns the move in UCI notation (e.g. "e2e4", "e7e8q", "e1g1" for O-O).
+// From and To must be resolved squares.
+func (m Move) ToUCI() string {
+	switch m.Castle {
+	case "O-O":
+		return m.From + "g" + m.From[1:]
+	case "O-O-O":
+		return m.From + "c" + m.From[1:]
+	}
+	return m.From + m.To + strings.ToLower(m.Promote)
+}
+
+// Equals reports whether two moves are the same move: same squares, piece, castling side and
+// promotion piece, so "a7a8q" and "a7a8n" differ. The promotion letter's case is ignored.
+func (m Move) Equals(other Move) bool {
+	return m.From == other.From && m.To == other.To && m.Piece == other.Piece &&
+		m.Castle == other.Castle && strings.EqualFold(m.Promote, other.Promote)
+}
+
+// IsCapture reports whether the move captures, including en passant
+func (m Move) IsCapture() bool {
+	return m.Capture || m.EnPassant
+}
+
+// IsPromotion reports whether the move promotes a pawn
+func (m Move) IsPromotion() bool {
+	return m.Promote != ""
+}
+
+// IsCastle reports whether the move is a castling move
+func (m Move) IsCastle() bool {
+	return m.Castle != ""
+}
+
+// IsQuiet reports whether the move neither captures, promotes nor castles
+func (m Move) IsQuiet() bool {
+	return !m.IsCapture() && !m.IsPromotion() && !m.IsCastle()
+}
+
+// IsTactical reports whether the move captures, promotes or castles
+func (m Move) IsTactical() bool {
+	return !m.IsQuiet()
+}
+
+// dashReplacer turns the dash variants found in typeset and OCR'd sources into hyphens
+var dashReplacer = strings.NewReplacer("\u2212", "-", "\u2013", "-", "\u2014", "-")
+
+// NormalizeMoveNotation rewrites the notation variants of PGN sources to the form
+// ParseAlgebraic expects: castling with zeros ("0-0") or other dashes ("O\u2212O") becomes
+// "O-O", and an en passant suffix ("exd6 e.p.") is dropped. Check marks are kept.
+func NormalizeMoveNotation(s string) string {
+	notation, _ := normalizeNotation(s)
+	return notation
+}
+
+// normalizeNotation implements NormalizeMoveNotation and reports whether the notation had an en passant suffix
+func normalizeNotation(s string) (string, bool) {
+	notation := strings.TrimSpace(dashReplacer.Replace(s))
+
+	// Squares only use the digits 1-8, so a zero can only be a castling O
+	notation = strings.ReplaceAll(notation, "0", "O")
+
+	// The suffix may come before or after the check mark, e.g. "exd6+ e.p." or "exd6 e.p.+"
+	checkMarks := notation[len(strings.TrimRight(notation, "+#")):]
+	notation = strings.TrimRight(notation, "+#")
+	enPassant := false
+	for _, suffix := range []string{"e.p.", "ep"} {
+		if trimmed := strings.TrimSuffix(notation, suffix); trimmed != notation && trimmed != "" {
+			notation, enPassant = strings.TrimSpace(trimmed), true
+			break
+		}
+	}
+	return notation + checkMarks, enPassant
+}
+
 // ParseAlgebraic parses algebraic notation and returns a Move struct
 func ParseAlgebraic(notation string, isWhiteToMove bool) (*Move, error) {
-	notation = strings.TrimSpace(notation)
+	notation, enPassant := normalizeNotation(notation)
 	if notation == "" {
 		return nil, fmt.Errorf("empty move notation")
 	}
 
 	move := &Move{}
+	notation = strings.TrimRight(notation, "+#") // Remove check/mate symbols
 
 	// Handle castling moves
-	if notation == "O-O" || notation == "0-0" {
+	if notation == "O-O" {
 		move.Castle = "O-O"
 		if isWhiteToMove {
 			move.From = "e1"
@@ -37,7 +113,7 @@ func ParseAlgebraic(notation string, isWhiteToMove bool) (*Move, error) {
 		}
 		return move, nil
 	}
-	if notation == "O-O-O" || notation == "0-0-0" {
+	if notation == "O-O-O" {
 		move.Castle = "O-O-O"
 		if isWhiteToMove {
 			move.From = "e1"
@@ -47,8 +123,6 @@ func ParseAlgebraic(notation string, isWhiteToMove bool) (*Move, error) {
 		return move, nil
 	}
 
-	notation = strings.TrimRight(notation, "+#") // Remove check/mate symbols
-
 	// Handle pawn moves (e.g., "e4", "exd5", "a1=Q", "exd8=Q")
 	if len(notation) >= 2 && !isUpperCase(notation[0]) {
 		file := notation[0]
@@ -83,7 +157,15 @@ func ParseAlgebraic(notation string, isWhiteToMove bool) (*Move, error) {
 			move.From = string(file) + "*"
 		}
 
+		if file < 'a' || file > 'h' || !isSquare(move.To) {
+			return nil, fmt.Errorf("invalid pawn move: %s", notation)
+		}
+		if promotionPiece != "" && !strings.Contains("QRBN", promotionPiece) {
+			return nil, fmt.Errorf("invalid promotion piece: %s", promotionPiece)
+		}
+
 		move.Piece = "P"
+		move.EnPassant = enPassant && move.Capture
 		if promotionPiece != "" {
 			move.Promote = promotionPiece
 		}
@@ -92,6 +174,9 @@ func ParseAlgebraic(notation string, isWhiteToMove bool) (*Move, error) {
 
 	// Handle piece moves (e.g., "Nf3", "Bxe4", "Rae8", "R1e8")
 	if len(notation) >= 3 {
+		if !strings.ContainsRune("NBRQK", rune(notation[0])) {
+			return nil, fmt.Errorf("invalid piece: %c", notation[0])
+		}
 		move.Piece = string(notation[0])
 		idx := 1
 
@@ -99,7 +184,11 @@ func ParseAlgebraic(notation string, isWhiteToMove bool) (*Move, error) {
 		var disambiguation string
 
 		// Check if there's disambiguation before the capture or target square
-		if idx < len(notation) {
+		if len(notation) >= idx+4 && isSquare(notation[idx:idx+2]) && isSquare(strings.TrimPrefix(notation[idx+2:], "x")) {
+			// Full square disambiguation (e.g., "Qa1b2"), needed with three pieces on the target
+			disambiguation = notation[idx : idx+2]
+			idx += 2
+		} else if idx < len(notation) {
 			char := notation[idx]
 			// Check if it's a file letter (a-h) or rank number (1-8)
 			if (char >= 'a' && char <= 'h') || (char >= '1' && char <= '8') {
@@ -132,13 +221,11 @@ func ParseAlgebraic(notation string, isWhiteToMove bool) (*Move, error) {
 		}
 
 		// Get the target square (should be the last 2 characters)
-		if idx+1 < len(notation) {
+		if idx+2 == len(notation) {
 			to := notation[idx : idx+2]
 
 			// Validate the target square notation
-			if len(to) != 2 ||
-				to[0] < 'a' || to[0] > 'h' ||
-				to[1] < '1' || to[1] > '8' {
+			if !isSquare(to) {
 				return nil, fmt.Errorf("invalid target square: %q", to)
 			}
 			move.To = to
@@ -155,6 +242,11 @@ func ParseAlgebraic(notation string, isWhiteToMove bool) (*Move, error) {
 	return nil, fmt.Errorf("invalid move notation: %s", notation)
 }
 
+// isSquare reports whether s is a square name such as "e4"
+func isSquare(s string) bool {
+	return len(s) == 2 && s[0] >= 'a' && s[0] <= 'h' && s[1] >= '1' && s[1] <= '8'
+}
+
 func isUpperCase(c byte) bool {
 	return c >= 'A' && c <= 'Z'
 }
@@ -0,0 +1,146 @@
+package moves
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzParseAlgebraic(f *testing.F) {
+	seeds := []string{
+		"e4", "Nf3", "O-O", "O-O-O", "exd5", "a1=Q", "Raxe8", "R1e8", "Nbd7", "Qa1b2", "Qh4xe1",
+		"", "e", "00", "O-O-O-O",
+		// Former crashers
+		"0\xff", "e4x", "e8=K", "e8=", "exd8=X", "a1q",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, white := range []bool{true, false} {
+			move, err := ParseAlgebraic(s, white)
+			if err != nil {
+				continue
+			}
+			if move.Castle != "" {
+				if move.Castle != "O-O" && move.Castle != "O-O-O" {
+					t.Errorf("ParseAlgebraic(%q, %v): castle = %q", s, white, move.Castle)
+				}
+				continue
+			}
+			if len(move.To) != 2 {
+				t.Errorf("ParseAlgebraic(%q, %v): To = %q", s, white, move.To)
+			}
+			if len(move.Piece) != 1 || !strings.Contains("PNBRQK", move.Piece) {
+				t.Errorf("ParseAlgebraic(%q, %v): Piece = %q", s, white, move.Piece)
+			}
+			if move.Promote != "" && !strings.Contains("QRBN", move.Promote) {
+				t.Errorf("ParseAlgebraic(%q, %v): Promote = %q", s, white, move.Promote)
+			}
+		}
+	})
+}
+
+func TestMoveEquals(t *testing.T) {
+	e4 := Move{From: "e2", To: "e4", Piece: "P"}
+	queen := Move{From: "a7", To: "a8", Piece: "P", Promote: "Q"}
+
+	tests := []struct {
+		name string
+		a, b Move
+		want bool
+	}{
+		{"same move", e4, Move{From: "e2", To: "e4", Piece: "P"}, true},
+		{"check flag ignored", e4, Move{From: "e2", To: "e4", Piece: "P", Check: true}, true},
+		{"different source", e4, Move{From: "e3", To: "e4", Piece: "P"}, false},
+		{"different target", e4, Move{From: "e2", To: "e3", Piece: "P"}, false},
+		{"different piece", Move{From: "d1", To: "d4", Piece: "Q"}, Move{From: "d1", To: "d4", Piece: "R"}, false},
+		{"same promotion", queen, Move{From: "a7", To: "a8", Piece: "P", Promote: "Q"}, true},
+		{"promotion case ignored", queen, Move{From: "a7", To: "a8", Piece: "P", Promote: "q"}, true},
+		{"different promotion", queen, Move{From: "a7", To: "a8", Piece: "P", Promote: "N"}, false},
+		{"promotion and none", queen, Move{From: "a7", To: "a8", Piece: "P"}, false},
+		{"castling sides", Move{From: "e1", Castle: "O-O"}, Move{From: "e1", Castle: "O-O-O"}, false},
+	}
+
+	for _, test := range tests {
+		if got := test.a.Equals(test.b); got != test.want {
+			t.Errorf("%s: %+v.Equals(%+v) = %v, want %v", test.name, test.a, test.b, got, test.want)
+		}
+		if got := test.b.Equals(test.a); got != test.want {
+			t.Errorf("%s: Equals is not symmetric", test.name)
+		}
+	}
+}
+
+func TestNormalizeNotation(t *testing.T) {
+	tests := []struct {
+		in            string
+		want          string
+		wantEnPassant bool
+	}{
+		{"0-0", "O-O", false},
+		{"0-0-0", "O-O-O", false},
+		{"O−O", "O-O", false},
+		{"O–O-O", "O-O-O", false},
+		{"0—0", "O-O", false},
+		{"O-O+", "O-O+", false},
+		{"0-0-0#", "O-O-O#", false},
+		{"exd6 e.p.", "exd6", true},
+		{"exd6ep", "exd6", true},
+		{"exd6e.p.", "exd6", true},
+		// The check mark may come before or after the suffix
+		{"exd6+ e.p.", "exd6+", true},
+		{"exd6 e.p.+", "exd6+", true},
+		{"exd6ep#", "exd6#", true},
+		{" Nf3 ", "Nf3", false},
+		{"e4", "e4", false},
+		// A bare suffix is not a move with a suffix
+		{"ep", "ep", false},
+	}
+
+	for _, test := range tests {
+		got, enPassant := normalizeNotation(test.in)
+		if got != test.want || enPassant != test.wantEnPassant {
+			t.Errorf("normalizeNotation(%q) = %q, %v, want %q, %v", test.in, got, enPassant, test.want, test.wantEnPassant)
+		}
+		if exported := NormalizeMoveNotation(test.in); exported != test.want {
+			t.Errorf("NormalizeMoveNotation(%q) = %q, want %q", test.in, exported, test.want)
+		}
+	}
+}
+
+func TestParseAlgebraicNotationVariants(t *testing.T) {
+	tests := []struct {
+		notation string
+		white    bool
+		want     Move
+	}{
+		{"0-0", true, Move{From: "e1", Castle: "O-O"}},
+		{"0-0-0", false, Move{From: "e8", Castle: "O-O-O"}},
+		{"O−O", true, Move{From: "e1", Castle: "O-O"}},
+		{"O–O–O", true, Move{From: "e1", Castle: "O-O-O"}},
+		{"O—O", false, Move{From: "e8", Castle: "O-O"}},
+		{"O-O+", true, Move{From: "e1", Castle: "O-O"}},
+		{"exd6 e.p.", true, Move{From: "e*", To: "d6", Piece: "P", Capture: true, EnPassant: true}},
+		{"exd6ep", true, Move{From: "e*", To: "d6", Piece: "P", Capture: true, EnPassant: true}},
+		{"exd6+ e.p.", true, Move{From: "e*", To: "d6", Piece: "P", Capture: true, EnPassant: true}},
+		{"exd6 e.p.+", true, Move{From: "e*", To: "d6", Piece: "P", Capture: true, EnPassant: true}},
+		{"dxe3ep", false, Move{From: "d*", To: "e3", Piece: "P", Capture: true, EnPassant: true}},
+		// Only captures can be en passant
+		{"e6 e.p.", true, Move{To: "e6", Piece: "P"}},
+		// Full square disambiguation
+		{"Qa1b2", true, Move{From: "a1", To: "b2", Piece: "Q"}},
+		{"Qa1xb2+", true, Move{From: "a1", To: "b2", Piece: "Q", Capture: true}},
+	}
+
+	for _, test := range tests {
+		move, err := ParseAlgebraic(test.notation, test.white)
+		if err != nil {
+			t.Errorf("ParseAlgebraic(%q, %v): %v", test.notation, test.white, err)
+			continue
+		}
+		if *move != test.want {
+			t.Errorf("ParseAlgebraic(%q, %v) = %+v, want %+v", test.notation, test.white, *move, test.want)
+		}
+	}
+}
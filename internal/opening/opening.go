@@ -0,0 +1,405 @@
+// Package opening names chess openings by their ECO code from the moves played
+package opening
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// ECOEntry is one line of the ECO table
+type ECOEntry struct {
+	ECO  string // Encyclopaedia of Chess Openings code, e.g. "C42"
+	Name string
+}
+
+// table maps space-separated UCI move prefixes to the opening they reach
+var table = buildTable()
+
+// maxPlies is the length of the longest line in the table
+var maxPlies int
+
+// Identify returns the name and ECO code of the most specific opening whose moves
+// start the game (the longest matching prefix). Both are empty if nothing matches.
+func Identify(moves []string) (name, eco string) {
+	plies := len(moves)
+	if plies > maxPlies {
+		plies = maxPlies
+	}
+
+	for ; plies > 0; plies-- {
+		if entry, exists := table[strings.Join(moves[:plies], " ")]; exists {
+			return entry.Name, entry.ECO
+		}
+	}
+	return "", ""
+}
+
+// buildTable converts the SAN lines below into the UCI-keyed lookup table
+func buildTable() map[string]ECOEntry {
+	result := make(map[string]ECOEntry, len(lines))
+
+	for _, line := range lines {
+		b := board.NewBoard()
+		if _, err := b.ApplyMovesFromSAN(strings.Fields(line.moves)); err != nil {
+			panic(fmt.Sprintf("opening %s %q: %v", line.eco, line.name, err))
+		}
+
+		result[strings.Join(b.UCIMoves, " ")] = ECOEntry{ECO: line.eco, Name: line.name}
+		if len(b.UCIMoves) > maxPlies {
+			maxPlies = len(b.UCIMoves)
+		}
+	}
+
+	return result
+}
+
+// lines is the ECO table, written in SAN for readability
+var lines = []struct {
+	eco, name, moves string
+}{
+	// A: flank openings, Queen's pawn without 1...d5, Indian systems, Dutch
+	{"A00", "Polish Opening", "b4"},
+	{"A00", "Grob Opening", "g4"},
+	{"A00", "Van't Kruijs Opening", "e3"},
+	{"A00", "Mieses Opening", "d3"},
+	{"A00", "Saragossa Opening", "c3"},
+	{"A00", "Anderssen's Opening", "a3"},
+	{"A00", "Clemenz Opening", "h3"},
+	{"A00", "Ware Opening", "a4"},
+	{"A00", "Barnes Opening", "f3"},
+	{"A00", "Hungarian Opening", "g3"},
+	{"A00", "Amar Opening", "Nh3"},
+	{"A00", "Van Geet Opening", "Nc3"},
+	{"A01", "Nimzo-Larsen Attack", "b3"},
+	{"A02", "Bird's Opening", "f4"},
+	{"A02", "Bird's Opening: From's Gambit", "f4 e5"},
+	{"A03", "Bird's Opening: Dutch Variation", "f4 d5"},
+	{"A04", "Zukertort Opening", "Nf3"},
+	{"A05", "Zukertort Opening", "Nf3 Nf6"},
+	{"A06", "Zukertort Opening", "Nf3 d5"},
+	{"A07", "King's Indian Attack", "Nf3 d5 g3"},
+	{"A09", "Réti Opening", "Nf3 d5 c4"},
+	{"A10", "English Opening", "c4"},
+	{"A10", "English Opening: Anglo-Dutch Defense", "c4 f5"},
+	{"A10", "English Opening: Great Snake Variation", "c4 g6"},
+	{"A11", "English Opening: Caro-Kann Defensive System", "c4 c6"},
+	{"A13", "English Opening: Agincourt Defense", "c4 e6"},
+	{"A15", "English Opening: Anglo-Indian Defense", "c4 Nf6"},
+	{"A16", "English Opening: Anglo-Indian Defense, Queen's Knight Variation", "c4 Nf6 Nc3"},
+	{"A17", "English Opening: Anglo-Indian Defense, Hedgehog System", "c4 Nf6 Nc3 e6"},
+	{"A18", "English Opening: Mikenas-Carls Variation", "c4 Nf6 Nc3 e6 e4"},
+	{"A20", "English Opening: King's English Variation", "c4 e5"},
+	{"A21", "English Opening: King's English Variation, Reversed Sicilian", "c4 e5 Nc3"},
+	{"A22", "English Opening: King's English Variation, Two Knights Variation", "c4 e5 Nc3 Nf6"},
+	{"A25", "English Opening: King's English Variation, Reversed Closed Sicilian", "c4 e5 Nc3 Nc6"},
+	{"A27", "English Opening: King's English Variation, Three Knights System", "c4 e5 Nc3 Nc6 Nf3"},
+	{"A28", "English Opening: King's English Variation, Four Knights Variation", "c4 e5 Nc3 Nc6 Nf3 Nf6"},
+	{"A30", "English Opening: Symmetrical Variation", "c4 c5"},
+	{"A34", "English Opening: Symmetrical Variation, Normal Variation", "c4 c5 Nc3"},
+	{"A40", "Queen's Pawn Game", "d4"},
+	{"A40", "Englund Gambit", "d4 e5"},
+	{"A40", "Horwitz Defense", "d4 e6"},
+	{"A40", "Modern Defense", "d4 g6"},
+	{"A40", "English Defense", "d4 e6 c4 b6"},
+	{"A41", "Queen's Pawn Game: Wade Defense", "d4 d6"},
+	{"A43", "Benoni Defense: Old Benoni", "d4 c5"},
+	{"A44", "Benoni Defense: Old Benoni Defense", "d4 c5 d5 e5"},
+	{"A45", "Indian Defense", "d4 Nf6"},
+	{"A45", "Trompowsky Attack", "d4 Nf6 Bg5"},
+	{"A45", "Indian Defense: London System", "d4 Nf6 Bf4"},
+	{"A46", "Indian Defense: Knights Variation", "d4 Nf6 Nf3"},
+	{"A46", "Torre Attack", "d4 Nf6 Nf3 e6 Bg5"},
+	{"A47", "Queen's Indian Defense", "d4 Nf6 Nf3 b6"},
+	{"A48", "East Indian Defense", "d4 Nf6 Nf3 g6"},
+	{"A48", "East Indian Defense: London System", "d4 Nf6 Nf3 g6 Bf4"},
+	{"A50", "Indian Defense: Normal Variation", "d4 Nf6 c4"},
+	{"A51", "Indian Defense: Budapest Defense", "d4 Nf6 c4 e5"},
+	{"A52", "Indian Defense: Budapest Defense, Adler Variation", "d4 Nf6 c4 e5 dxe5 Ng4"},
+	{"A53", "Old Indian Defense", "d4 Nf6 c4 d6"},
+	{"A56", "Benoni Defense", "d4 Nf6 c4 c5"},
+	{"A56", "Benoni Defense: Czech Benoni", "d4 Nf6 c4 c5 d5 e5"},
+	{"A57", "Benko Gambit", "d4 Nf6 c4 c5 d5 b5"},
+	{"A60", "Benoni Defense: Modern Variation", "d4 Nf6 c4 c5 d5 e6"},
+	{"A80", "Dutch Defense", "d4 f5"},
+	{"A81", "Dutch Defense: Fianchetto Attack", "d4 f5 g3"},
+	{"A83", "Dutch Defense: Staunton Gambit", "d4 f5 e4"},
+	{"A84", "Dutch Defense: Normal Variation", "d4 f5 c4"},
+	{"A85", "Dutch Defense: Queen's Knight Variation", "d4 f5 c4 Nf6 Nc3"},
+	{"A86", "Dutch Defense: Fianchetto Variation", "d4 f5 c4 Nf6 g3"},
+
+	// B: 1.e4 without 1...e5 or 1...e6 (Sicilian, Caro-Kann, Pirc, Alekhine, Scandinavian)
+	{"B00", "King's Pawn Game", "e4"},
+	{"B00", "Nimzowitsch Defense", "e4 Nc6"},
+	{"B00", "Owen Defense", "e4 b6"},
+	{"B00", "St. George Defense", "e4 a6"},
+	{"B01", "Scandinavian Defense", "e4 d5"},
+	{"B01", "Scandinavian Defense: Main Line", "e4 d5 exd5 Qxd5"},
+	{"B01", "Scandinavian Defense: Main Line, Mieses Variation", "e4 d5 exd5 Qxd5 Nc3 Qa5"},
+	{"B01", "Scandinavian Defense: Modern Variation", "e4 d5 exd5 Nf6"},
+	{"B02", "Alekhine Defense", "e4 Nf6"},
+	{"B02", "Alekhine Defense: Scandinavian Variation", "e4 Nf6 Nc3 d5"},
+	{"B03", "Alekhine Defense", "e4 Nf6 e5 Nd5 d4"},
+	{"B03", "Alekhine Defense: Four Pawns Attack", "e4 Nf6 e5 Nd5 d4 d6 c4 Nb6 f4"},
+	{"B03", "Alekhine Defense: Exchange Variation", "e4 Nf6 e5 Nd5 d4 d6 c4 Nb6 exd6"},
+	{"B04", "Alekhine Defense: Modern Variation", "e4 Nf6 e5 Nd5 d4 d6 Nf3"},
+	{"B05", "Alekhine Defense: Modern Variation, Main Line", "e4 Nf6 e5 Nd5 d4 d6 Nf3 Bg4"},
+	{"B06", "Modern Defense", "e4 g6"},
+	{"B06", "Modern Defense: Standard Line", "e4 g6 d4 Bg7"},
+	{"B07", "Pirc Defense", "e4 d6 d4 Nf6"},
+	{"B07", "Pirc Defense: Main Line", "e4 d6 d4 Nf6 Nc3 g6"},
+	{"B08", "Pirc Defense: Classical Variation", "e4 d6 d4 Nf6 Nc3 g6 Nf3"},
+	{"B09", "Pirc Defense: Austrian Attack", "e4 d6 d4 Nf6 Nc3 g6 f4"},
+	{"B10", "Caro-Kann Defense", "e4 c6"},
+	{"B10", "Caro-Kann Defense: Accelerated Panov Attack", "e4 c6 c4"},
+	{"B11", "Caro-Kann Defense: Two Knights Attack", "e4 c6 Nc3 d5 Nf3"},
+	{"B12", "Caro-Kann Defense", "e4 c6 d4 d5"},
+	{"B12", "Caro-Kann Defense: Advance Variation", "e4 c6 d4 d5 e5"},
+	{"B12", "Caro-Kann Defense: Advance Variation, Short Variation", "e4 c6 d4 d5 e5 Bf5 Nf3 e6 Be2"},
+	{"B13", "Caro-Kann Defense: Exchange Variation", "e4 c6 d4 d5 exd5 cxd5"},
+	{"B13", "Caro-Kann Defense: Panov Attack", "e4 c6 d4 d5 exd5 cxd5 c4"},
+	{"B15", "Caro-Kann Defense: Main Line", "e4 c6 d4 d5 Nc3"},
+	{"B15", "Caro-Kann Defense", "e4 c6 d4 d5 Nc3 dxe4 Nxe4"},
+	{"B16", "Caro-Kann Defense: Bronstein-Larsen Variation", "e4 c6 d4 d5 Nc3 dxe4 Nxe4 Nf6 Nxf6+ gxf6"},
+	{"B17", "Caro-Kann Defense: Karpov Variation", "e4 c6 d4 d5 Nc3 dxe4 Nxe4 Nd7"},
+	{"B18", "Caro-Kann Defense: Classical Variation", "e4 c6 d4 d5 Nc3 dxe4 Nxe4 Bf5"},
+	{"B20", "Sicilian Defense", "e4 c5"},
+	{"B20", "Sicilian Defense: Wing Gambit", "e4 c5 b4"},
+	{"B21", "Sicilian Defense: McDonnell Attack", "e4 c5 f4"},
+	{"B21", "Sicilian Defense: Smith-Morra Gambit", "e4 c5 d4 cxd4 c3"},
+	{"B22", "Sicilian Defense: Alapin Variation", "e4 c5 c3"},
+	{"B23", "Sicilian Defense: Closed", "e4 c5 Nc3"},
+	{"B24", "Sicilian Defense: Closed", "e4 c5 Nc3 Nc6 g3"},
+	{"B27", "Sicilian Defense", "e4 c5 Nf3"},
+	{"B27", "Sicilian Defense: Hyperaccelerated Dragon", "e4 c5 Nf3 g6"},
+	{"B28", "Sicilian Defense: O'Kelly Variation", "e4 c5 Nf3 a6"},
+	{"B29", "Sicilian Defense: Nimzowitsch Variation", "e4 c5 Nf3 Nf6"},
+	{"B30", "Sicilian Defense: Old Sicilian", "e4 c5 Nf3 Nc6"},
+	{"B30", "Sicilian Defense: Rossolimo Variation", "e4 c5 Nf3 Nc6 Bb5"},
+	{"B32", "Sicilian Defense: Open", "e4 c5 Nf3 Nc6 d4 cxd4 Nxd4"},
+	{"B33", "Sicilian Defense: Lasker-Pelikan Variation", "e4 c5 Nf3 Nc6 d4 cxd4 Nxd4 Nf6 Nc3 e5"},
+	{"B33", "Sicilian Defense: Sveshnikov Variation", "e4 c5 Nf3 Nc6 d4 cxd4 Nxd4 Nf6 Nc3 e5 Ndb5 d6"},
+	{"B34", "Sicilian Defense: Accelerated Dragon", "e4 c5 Nf3 Nc6 d4 cxd4 Nxd4 g6"},
+	{"B36", "Sicilian Defense: Accelerated Dragon, Maróczy Bind", "e4 c5 Nf3 Nc6 d4 cxd4 Nxd4 g6 c4"},
+	{"B40", "Sicilian Defense: French Variation", "e4 c5 Nf3 e6"},
+	{"B40", "Sicilian Defense: Open", "e4 c5 Nf3 e6 d4 cxd4 Nxd4"},
+	{"B41", "Sicilian Defense: Kan Variation", "e4 c5 Nf3 e6 d4 cxd4 Nxd4 a6"},
+	{"B44", "Sicilian Defense: Taimanov Variation", "e4 c5 Nf3 e6 d4 cxd4 Nxd4 Nc6"},
+	{"B45", "Sicilian Defense: Four Knights Variation", "e4 c5 Nf3 e6 d4 cxd4 Nxd4 Nf6 Nc3 Nc6"},
+	{"B50", "Sicilian Defense: Modern Variations", "e4 c5 Nf3 d6"},
+	{"B51", "Sicilian Defense: Moscow Variation", "e4 c5 Nf3 d6 Bb5+"},
+	{"B53", "Sicilian Defense: Chekhover Variation", "e4 c5 Nf3 d6 d4 cxd4 Qxd4"},
+	{"B54", "Sicilian Defense: Open", "e4 c5 Nf3 d6 d4 cxd4 Nxd4"},
+	{"B56", "Sicilian Defense: Open", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3"},
+	{"B58", "Sicilian Defense: Classical Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 Nc6"},
+	{"B60", "Sicilian Defense: Richter-Rauzer Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 Nc6 Bg5"},
+	{"B70", "Sicilian Defense: Dragon Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 g6"},
+	{"B72", "Sicilian Defense: Dragon Variation, Classical Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 g6 Be3"},
+	{"B75", "Sicilian Defense: Dragon Variation, Yugoslav Attack", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 g6 Be3 Bg7 f3"},
+	{"B80", "Sicilian Defense: Scheveningen Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 e6"},
+	{"B90", "Sicilian Defense: Najdorf Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6"},
+	{"B90", "Sicilian Defense: Najdorf Variation, English Attack", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6 Be3"},
+	{"B90", "Sicilian Defense: Najdorf Variation, Adams Attack", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6 h3"},
+	{"B91", "Sicilian Defense: Najdorf Variation, Zagreb Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6 g3"},
+	{"B92", "Sicilian Defense: Najdorf Variation, Opocensky Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6 Be2"},
+	{"B94", "Sicilian Defense: Najdorf Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6 Bg5"},
+	{"B96", "Sicilian Defense: Najdorf Variation", "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6 Bg5 e6 f4"},
+
+	// C: 1.e4 e6 (French) and 1.e4 e5 (open games, Ruy Lopez)
+	{"C00", "French Defense", "e4 e6"},
+	{"C00", "French Defense: Chigorin Variation", "e4 e6 Qe2"},
+	{"C00", "French Defense: King's Indian Attack", "e4 e6 d3"},
+	{"C00", "French Defense: Normal Variation", "e4 e6 d4 d5"},
+	{"C01", "French Defense: Exchange Variation", "e4 e6 d4 d5 exd5"},
+	{"C02", "French Defense: Advance Variation", "e4 e6 d4 d5 e5"},
+	{"C02", "French Defense: Advance Variation, Main Line", "e4 e6 d4 d5 e5 c5 c3 Nc6 Nf3 Qb6"},
+	{"C03", "French Defense: Tarrasch Variation", "e4 e6 d4 d5 Nd2"},
+	{"C05", "French Defense: Tarrasch Variation, Closed Variation", "e4 e6 d4 d5 Nd2 Nf6"},
+	{"C07", "French Defense: Tarrasch Variation, Open System", "e4 e6 d4 d5 Nd2 c5"},
+	{"C10", "French Defense: Paulsen Variation", "e4 e6 d4 d5 Nc3"},
+	{"C10", "French Defense: Rubinstein Variation", "e4 e6 d4 d5 Nc3 dxe4"},
+	{"C11", "French Defense: Classical Variation", "e4 e6 d4 d5 Nc3 Nf6"},
+	{"C11", "French Defense: Steinitz Variation", "e4 e6 d4 d5 Nc3 Nf6 e5"},
+	{"C12", "French Defense: MacCutcheon Variation", "e4 e6 d4 d5 Nc3 Nf6 Bg5 Bb4"},
+	{"C13", "French Defense: Classical Variation", "e4 e6 d4 d5 Nc3 Nf6 Bg5 Be7"},
+	{"C15", "French Defense: Winawer Variation", "e4 e6 d4 d5 Nc3 Bb4"},
+	{"C16", "French Defense: Winawer Variation, Advance Variation", "e4 e6 d4 d5 Nc3 Bb4 e5"},
+	{"C17", "French Defense: Winawer Variation, Advance Variation", "e4 e6 d4 d5 Nc3 Bb4 e5 c5"},
+	{"C18", "French Defense: Winawer Variation", "e4 e6 d4 d5 Nc3 Bb4 e5 c5 a3"},
+	{"C20", "King's Pawn Game", "e4 e5"},
+	{"C20", "King's Pawn Game: Wayward Queen Attack", "e4 e5 Qh5"},
+	{"C20", "King's Pawn Game: Napoleon Attack", "e4 e5 Qf3"},
+	{"C21", "Center Game", "e4 e5 d4 exd4"},
+	{"C21", "Danish Gambit", "e4 e5 d4 exd4 c3"},
+	{"C22", "Center Game: Normal Variation", "e4 e5 d4 exd4 Qxd4 Nc6"},
+	{"C23", "Bishop's Opening", "e4 e5 Bc4"},
+	{"C24", "Bishop's Opening: Berlin Defense", "e4 e5 Bc4 Nf6"},
+	{"C25", "Vienna Game", "e4 e5 Nc3"},
+	{"C25", "Vienna Game: Max Lange Defense", "e4 e5 Nc3 Nc6"},
+	{"C26", "Vienna Game: Falkbeer Variation", "e4 e5 Nc3 Nf6"},
+	{"C29", "Vienna Game: Vienna Gambit", "e4 e5 Nc3 Nf6 f4"},
+	{"C30", "King's Gambit", "e4 e5 f4"},
+	{"C30", "King's Gambit Declined: Classical Variation", "e4 e5 f4 Bc5"},
+	{"C31", "King's Gambit Declined: Falkbeer Countergambit", "e4 e5 f4 d5"},
+	{"C33", "King's Gambit Accepted", "e4 e5 f4 exf4"},
+	{"C33", "King's Gambit Accepted: Bishop's Gambit", "e4 e5 f4 exf4 Bc4"},
+	{"C34", "King's Gambit Accepted: King's Knight's Gambit", "e4 e5 f4 exf4 Nf3"},
+	{"C35", "King's Gambit Accepted: Cunningham Defense", "e4 e5 f4 exf4 Nf3 Be7"},
+	{"C36", "King's Gambit Accepted: Modern Defense", "e4 e5 f4 exf4 Nf3 d5"},
+	{"C40", "King's Knight Opening", "e4 e5 Nf3"},
+	{"C40", "Latvian Gambit", "e4 e5 Nf3 f5"},
+	{"C40", "Elephant Gambit", "e4 e5 Nf3 d5"},
+	{"C41", "Philidor Defense", "e4 e5 Nf3 d6"},
+	{"C41", "Philidor Defense: Exchange Variation", "e4 e5 Nf3 d6 d4 exd4"},
+	{"C42", "Petrov's Defense", "e4 e5 Nf3 Nf6"},
+	{"C42", "Petrov's Defense: Classical Attack", "e4 e5 Nf3 Nf6 Nxe5 d6 Nf3 Nxe4"},
+	{"C43", "Petrov's Defense: Steinitz Attack", "e4 e5 Nf3 Nf6 d4"},
+	{"C44", "King's Pawn Game: Tayler Opening", "e4 e5 Nf3 Nc6 Be2"},
+	{"C44", "King's Knight Opening: Normal Variation", "e4 e5 Nf3 Nc6"},
+	{"C44", "Ponziani Opening", "e4 e5 Nf3 Nc6 c3"},
+	{"C44", "Scotch Game", "e4 e5 Nf3 Nc6 d4"},
+	{"C44", "Scotch Gambit", "e4 e5 Nf3 Nc6 d4 exd4 Bc4"},
+	{"C45", "Scotch Game", "e4 e5 Nf3 Nc6 d4 exd4 Nxd4"},
+	{"C45", "Scotch Game: Classical Variation", "e4 e5 Nf3 Nc6 d4 exd4 Nxd4 Bc5"},
+	{"C45", "Scotch Game: Schmidt Variation", "e4 e5 Nf3 Nc6 d4 exd4 Nxd4 Nf6"},
+	{"C46", "Three Knights Opening", "e4 e5 Nf3 Nc6 Nc3"},
+	{"C47", "Four Knights Game", "e4 e5 Nf3 Nc6 Nc3 Nf6"},
+	{"C47", "Four Knights Game: Scotch Variation", "e4 e5 Nf3 Nc6 Nc3 Nf6 d4"},
+	{"C48", "Four Knights Game: Spanish Variation", "e4 e5 Nf3 Nc6 Nc3 Nf6 Bb5"},
+	{"C50", "Italian Game", "e4 e5 Nf3 Nc6 Bc4"},
+	{"C50", "Italian Game: Hungarian Defense", "e4 e5 Nf3 Nc6 Bc4 Be7"},
+	{"C50", "Italian Game: Giuoco Piano", "e4 e5 Nf3 Nc6 Bc4 Bc5"},
+	{"C50", "Italian Game: Giuoco Pianissimo", "e4 e5 Nf3 Nc6 Bc4 Bc5 d3"},
+	{"C51", "Italian Game: Evans Gambit", "e4 e5 Nf3 Nc6 Bc4 Bc5 b4"},
+	{"C53", "Italian Game: Classical Variation", "e4 e5 Nf3 Nc6 Bc4 Bc5 c3"},
+	{"C54", "Italian Game: Classical Variation, Center Attack", "e4 e5 Nf3 Nc6 Bc4 Bc5 c3 Nf6 d4"},
+	{"C55", "Italian Game: Two Knights Defense", "e4 e5 Nf3 Nc6 Bc4 Nf6"},
+	{"C55", "Italian Game: Two Knights Defense, Modern Bishop's Opening", "e4 e5 Nf3 Nc6 Bc4 Nf6 d3"},
+	{"C55", "Italian Game: Scotch Gambit", "e4 e5 Nf3 Nc6 Bc4 Nf6 d4"},
+	{"C57", "Italian Game: Two Knights Defense, Knight Attack", "e4 e5 Nf3 Nc6 Bc4 Nf6 Ng5"},
+	{"C57", "Italian Game: Two Knights Defense, Traxler Counterattack", "e4 e5 Nf3 Nc6 Bc4 Nf6 Ng5 Bc5"},
+	{"C57", "Italian Game: Two Knights Defense, Fried Liver Attack", "e4 e5 Nf3 Nc6 Bc4 Nf6 Ng5 d5 exd5 Nxd5 Nxf7"},
+	{"C58", "Italian Game: Two Knights Defense, Polerio Defense", "e4 e5 Nf3 Nc6 Bc4 Nf6 Ng5 d5 exd5 Na5"},
+	{"C60", "Ruy Lopez", "e4 e5 Nf3 Nc6 Bb5"},
+	{"C60", "Ruy Lopez: Cozio Defense", "e4 e5 Nf3 Nc6 Bb5 Nge7"},
+	{"C61", "Ruy Lopez: Bird Variation", "e4 e5 Nf3 Nc6 Bb5 Nd4"},
+	{"C62", "Ruy Lopez: Steinitz Defense", "e4 e5 Nf3 Nc6 Bb5 d6"},
+	{"C63", "Ruy Lopez: Schliemann Defense", "e4 e5 Nf3 Nc6 Bb5 f5"},
+	{"C64", "Ruy Lopez: Classical Variation", "e4 e5 Nf3 Nc6 Bb5 Bc5"},
+	{"C65", "Ruy Lopez: Berlin Defense", "e4 e5 Nf3 Nc6 Bb5 Nf6"},
+	{"C67", "Ruy Lopez: Berlin Defense, Rio Gambit Accepted", "e4 e5 Nf3 Nc6 Bb5 Nf6 O-O Nxe4"},
+	{"C67", "Ruy Lopez: Berlin Defense, Berlin Wall", "e4 e5 Nf3 Nc6 Bb5 Nf6 O-O Nxe4 d4 Nd6 Bxc6 dxc6 dxe5 Nf5 Qxd8+ Kxd8"},
+	{"C68", "Ruy Lopez: Exchange Variation", "e4 e5 Nf3 Nc6 Bb5 a6 Bxc6"},
+	{"C70", "Ruy Lopez: Morphy Defense", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4"},
+	{"C71", "Ruy Lopez: Morphy Defense, Modern Steinitz Defense", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 d6"},
+	{"C77", "Ruy Lopez: Morphy Defense", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6"},
+	{"C78", "Ruy Lopez: Morphy Defense", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O"},
+	{"C80", "Ruy Lopez: Open", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Nxe4"},
+	{"C84", "Ruy Lopez: Closed", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7"},
+	{"C85", "Ruy Lopez: Closed, Delayed Exchange", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Bxc6"},
+	{"C86", "Ruy Lopez: Worrall Attack", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Qe2"},
+	{"C87", "Ruy Lopez: Closed", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1"},
+	{"C88", "Ruy Lopez: Closed", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3"},
+	{"C89", "Ruy Lopez: Marshall Attack", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3 O-O c3 d5"},
+	{"C90", "Ruy Lopez: Closed", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3 d6 c3 O-O"},
+	{"C92", "Ruy Lopez: Closed", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3 d6 c3 O-O h3"},
+	{"C92", "Ruy Lopez: Closed, Zaitsev System", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3 d6 c3 O-O h3 Bb7"},
+	{"C95", "Ruy Lopez: Closed, Breyer Defense", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3 d6 c3 O-O h3 Nb8"},
+	{"C96", "Ruy Lopez: Closed, Chigorin Defense", "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3 d6 c3 O-O h3 Na5 Bc2"},
+
+	// D: 1.d4 d5 (Queen's Gambit, Slav) and the Grünfeld
+	{"D00", "Queen's Pawn Game", "d4 d5"},
+	{"D00", "Queen's Pawn Game: Accelerated London System", "d4 d5 Bf4"},
+	{"D00", "Blackmar-Diemer Gambit", "d4 d5 e4"},
+	{"D01", "Richter-Veresov Attack", "d4 d5 Nc3 Nf6 Bg5"},
+	{"D01", "Rapport-Jobava System", "d4 d5 Nc3 Nf6 Bf4"},
+	{"D02", "Queen's Pawn Game: Zukertort Variation", "d4 d5 Nf3"},
+	{"D02", "Queen's Pawn Game: London System", "d4 d5 Nf3 Nf6 Bf4"},
+	{"D04", "Queen's Pawn Game: Colle System", "d4 d5 Nf3 Nf6 e3"},
+	{"D05", "Queen's Pawn Game: Colle System", "d4 d5 Nf3 Nf6 e3 e6 Bd3"},
+	{"D06", "Queen's Gambit", "d4 d5 c4"},
+	{"D07", "Queen's Gambit Declined: Chigorin Defense", "d4 d5 c4 Nc6"},
+	{"D08", "Queen's Gambit Declined: Albin Countergambit", "d4 d5 c4 e5"},
+	{"D10", "Slav Defense", "d4 d5 c4 c6"},
+	{"D11", "Slav Defense: Modern Line", "d4 d5 c4 c6 Nf3"},
+	{"D12", "Slav Defense: Quiet Variation", "d4 d5 c4 c6 Nf3 Nf6 e3 Bf5"},
+	{"D13", "Slav Defense: Exchange Variation", "d4 d5 c4 c6 cxd5 cxd5"},
+	{"D15", "Slav Defense: Three Knights Variation", "d4 d5 c4 c6 Nf3 Nf6 Nc3"},
+	{"D15", "Slav Defense: Chebanenko Variation", "d4 d5 c4 c6 Nf3 Nf6 Nc3 a6"},
+	{"D16", "Slav Defense: Alapin Variation", "d4 d5 c4 c6 Nf3 Nf6 Nc3 dxc4 a4"},
+	{"D17", "Slav Defense: Czech Variation", "d4 d5 c4 c6 Nf3 Nf6 Nc3 dxc4 a4 Bf5"},
+	{"D20", "Queen's Gambit Accepted", "d4 d5 c4 dxc4"},
+	{"D20", "Queen's Gambit Accepted: Central Variation", "d4 d5 c4 dxc4 e4"},
+	{"D21", "Queen's Gambit Accepted", "d4 d5 c4 dxc4 Nf3"},
+	{"D25", "Queen's Gambit Accepted: Normal Variation", "d4 d5 c4 dxc4 Nf3 Nf6 e3"},
+	{"D26", "Queen's Gambit Accepted: Classical Defense", "d4 d5 c4 dxc4 Nf3 Nf6 e3 e6 Bxc4 c5"},
+	{"D30", "Queen's Gambit Declined", "d4 d5 c4 e6"},
+	{"D31", "Queen's Gambit Declined: Queen's Knight Variation", "d4 d5 c4 e6 Nc3"},
+	{"D32", "Tarrasch Defense", "d4 d5 c4 e6 Nc3 c5"},
+	{"D35", "Queen's Gambit Declined: Normal Defense", "d4 d5 c4 e6 Nc3 Nf6"},
+	{"D35", "Queen's Gambit Declined: Exchange Variation", "d4 d5 c4 e6 Nc3 Nf6 cxd5 exd5"},
+	{"D37", "Queen's Gambit Declined: Three Knights Variation", "d4 d5 c4 e6 Nc3 Nf6 Nf3"},
+	{"D37", "Queen's Gambit Declined: Harrwitz Attack", "d4 d5 c4 e6 Nc3 Nf6 Nf3 Be7 Bf4"},
+	{"D38", "Queen's Gambit Declined: Ragozin Defense", "d4 d5 c4 e6 Nc3 Nf6 Nf3 Bb4"},
+	{"D41", "Queen's Gambit Declined: Semi-Tarrasch Defense", "d4 d5 c4 e6 Nc3 Nf6 Nf3 c5"},
+	{"D43", "Semi-Slav Defense", "d4 d5 c4 e6 Nc3 Nf6 Nf3 c6"},
+	{"D44", "Semi-Slav Defense: Botvinnik System", "d4 d5 c4 e6 Nc3 Nf6 Nf3 c6 Bg5 dxc4"},
+	{"D45", "Semi-Slav Defense: Normal Variation", "d4 d5 c4 e6 Nc3 Nf6 Nf3 c6 e3"},
+	{"D46", "Semi-Slav Defense: Main Line", "d4 d5 c4 e6 Nc3 Nf6 Nf3 c6 e3 Nbd7 Bd3"},
+	{"D47", "Semi-Slav Defense: Meran Variation", "d4 d5 c4 e6 Nc3 Nf6 Nf3 c6 e3 Nbd7 Bd3 dxc4 Bxc4 b5"},
+	{"D50", "Queen's Gambit Declined: Modern Variation", "d4 d5 c4 e6 Nc3 Nf6 Bg5"},
+	{"D51", "Queen's Gambit Declined", "d4 d5 c4 e6 Nc3 Nf6 Bg5 Nbd7"},
+	{"D52", "Queen's Gambit Declined: Cambridge Springs Defense", "d4 d5 c4 e6 Nc3 Nf6 Bg5 Nbd7 e3 c6 Nf3 Qa5"},
+	{"D53", "Queen's Gambit Declined: Modern Variation, Normal Line", "d4 d5 c4 e6 Nc3 Nf6 Bg5 Be7"},
+	{"D55", "Queen's Gambit Declined: Modern Variation", "d4 d5 c4 e6 Nc3 Nf6 Bg5 Be7 e3 O-O Nf3"},
+	{"D58", "Queen's Gambit Declined: Tartakower Defense", "d4 d5 c4 e6 Nc3 Nf6 Bg5 Be7 e3 O-O Nf3 h6 Bh4 b6"},
+	{"D70", "Neo-Grünfeld Defense", "d4 Nf6 c4 g6 f3 d5"},
+	{"D80", "Grünfeld Defense", "d4 Nf6 c4 g6 Nc3 d5"},
+	{"D80", "Grünfeld Defense: Stockholm Variation", "d4 Nf6 c4 g6 Nc3 d5 Bg5"},
+	{"D82", "Grünfeld Defense: Brinckmann Attack", "d4 Nf6 c4 g6 Nc3 d5 Bf4"},
+	{"D85", "Grünfeld Defense: Exchange Variation", "d4 Nf6 c4 g6 Nc3 d5 cxd5 Nxd5"},
+	{"D85", "Grünfeld Defense: Exchange Variation, Main Line", "d4 Nf6 c4 g6 Nc3 d5 cxd5 Nxd5 e4 Nxc3 bxc3"},
+	{"D86", "Grünfeld Defense: Exchange Variation, Classical Variation", "d4 Nf6 c4 g6 Nc3 d5 cxd5 Nxd5 e4 Nxc3 bxc3 Bg7 Bc4"},
+	{"D90", "Grünfeld Defense: Three Knights Variation", "d4 Nf6 c4 g6 Nc3 d5 Nf3"},
+	{"D91", "Grünfeld Defense: Three Knights Variation, Petrosian System", "d4 Nf6 c4 g6 Nc3 d5 Nf3 Bg7 Bg5"},
+	{"D94", "Grünfeld Defense: Three Knights Variation, Burille Variation", "d4 Nf6 c4 g6 Nc3 d5 Nf3 Bg7 e3"},
+	{"D96", "Grünfeld Defense: Russian Variation", "d4 Nf6 c4 g6 Nc3 d5 Nf3 Bg7 Qb3"},
+
+	// E: Catalan, Queen's Indian, Bogo-Indian, Nimzo-Indian, King's Indian
+	{"E00", "Indian Defense: East Indian Defense", "d4 Nf6 c4 e6"},
+	{"E00", "Catalan Opening", "d4 Nf6 c4 e6 g3"},
+	{"E01", "Catalan Opening: Closed", "d4 Nf6 c4 e6 g3 d5 Bg2"},
+	{"E04", "Catalan Opening: Open Defense", "d4 Nf6 c4 e6 g3 d5 Bg2 dxc4 Nf3"},
+	{"E10", "Indian Defense: Anti-Nimzo-Indian", "d4 Nf6 c4 e6 Nf3"},
+	{"E10", "Blumenfeld Countergambit", "d4 Nf6 c4 e6 Nf3 c5 d5 b5"},
+	{"E11", "Bogo-Indian Defense", "d4 Nf6 c4 e6 Nf3 Bb4+"},
+	{"E12", "Queen's Indian Defense", "d4 Nf6 c4 e6 Nf3 b6"},
+	{"E12", "Queen's Indian Defense: Petrosian Variation", "d4 Nf6 c4 e6 Nf3 b6 a3"},
+	{"E15", "Queen's Indian Defense: Fianchetto Variation", "d4 Nf6 c4 e6 Nf3 b6 g3"},
+	{"E20", "Nimzo-Indian Defense", "d4 Nf6 c4 e6 Nc3 Bb4"},
+	{"E20", "Nimzo-Indian Defense: Kmoch Variation", "d4 Nf6 c4 e6 Nc3 Bb4 f3"},
+	{"E21", "Nimzo-Indian Defense: Three Knights Variation", "d4 Nf6 c4 e6 Nc3 Bb4 Nf3"},
+	{"E22", "Nimzo-Indian Defense: Spielmann Variation", "d4 Nf6 c4 e6 Nc3 Bb4 Qb3"},
+	{"E24", "Nimzo-Indian Defense: Sämisch Variation", "d4 Nf6 c4 e6 Nc3 Bb4 a3 Bxc3+ bxc3"},
+	{"E30", "Nimzo-Indian Defense: Leningrad Variation", "d4 Nf6 c4 e6 Nc3 Bb4 Bg5"},
+	{"E32", "Nimzo-Indian Defense: Classical Variation", "d4 Nf6 c4 e6 Nc3 Bb4 Qc2"},
+	{"E40", "Nimzo-Indian Defense: Normal Variation", "d4 Nf6 c4 e6 Nc3 Bb4 e3"},
+	{"E41", "Nimzo-Indian Defense: Hübner Variation", "d4 Nf6 c4 e6 Nc3 Bb4 e3 c5"},
+	{"E43", "Nimzo-Indian Defense: St. Petersburg Variation", "d4 Nf6 c4 e6 Nc3 Bb4 e3 b6"},
+	{"E46", "Nimzo-Indian Defense: Normal Variation", "d4 Nf6 c4 e6 Nc3 Bb4 e3 O-O"},
+	{"E48", "Nimzo-Indian Defense: Normal Variation, Bishop Attack", "d4 Nf6 c4 e6 Nc3 Bb4 e3 O-O Bd3 d5"},
+	{"E60", "King's Indian Defense", "d4 Nf6 c4 g6"},
+	{"E61", "King's Indian Defense", "d4 Nf6 c4 g6 Nc3 Bg7"},
+	{"E62", "King's Indian Defense: Fianchetto Variation", "d4 Nf6 c4 g6 Nc3 Bg7 Nf3 d6 g3"},
+	{"E70", "King's Indian Defense: Normal Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6"},
+	{"E73", "King's Indian Defense: Averbakh Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 Be2 O-O Bg5"},
+	{"E76", "King's Indian Defense: Four Pawns Attack", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 f4"},
+	{"E80", "King's Indian Defense: Sämisch Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 f3"},
+	{"E90", "King's Indian Defense: Normal Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 Nf3"},
+	{"E91", "King's Indian Defense: Orthodox Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 Nf3 O-O Be2"},
+	{"E92", "King's Indian Defense: Orthodox Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 Nf3 O-O Be2 e5"},
+	{"E92", "King's Indian Defense: Petrosian Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 Nf3 O-O Be2 e5 d5"},
+	{"E94", "King's Indian Defense: Orthodox Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 Nf3 O-O Be2 e5 O-O"},
+	{"E97", "King's Indian Defense: Mar del Plata Variation", "d4 Nf6 c4 g6 Nc3 Bg7 e4 d6 Nf3 O-O Be2 e5 O-O Nc6 d5 Ne7"},
+}
@@ -0,0 +1,115 @@
+package uci
+
+import (
+	"strconv"
+	"strings"
+)
+
+// InfoLine is the parsed content of a UCI "info" line
+type InfoLine struct {
+	Depth      int64
+	SelDepth   int64
+	Nodes      int64
+	NPS        int64
+	Time       int64 // Search time in ms
+	HashFull   int64 // Hash table fill, in permille
+	TBHits     int64
+	Score      int  // Centipawns for the side to move (0 for mate scores)
+	HasScore   bool // False when the line carries no score, e.g. currmove updates
+	IsMate     bool
+	MateIn     int  // Moves to mate, negative if the side to move is getting mated
+	LowerBound bool // Score is only a lower bound (fail high)
+	UpperBound bool // Score is only an upper bound (fail low)
+	PV         []string
+	MultiPV    int // Which PV line this is; 1 when the engine does not say
+}
+
+// ParseInfoLine parses a UCI "info" line. Fields may come in any order; unknown tokens and
+// malformed values are skipped, and an "info string" message ends the parse.
+func ParseInfoLine(line string) InfoLine {
+	info := InfoLine{MultiPV: 1}
+	parts := strings.Fields(line)
+
+	// int64At parses the value following the token at index i
+	int64At := func(i int) (int64, bool) {
+		if i+1 >= len(parts) {
+			return 0, false
+		}
+		value, err := strconv.ParseInt(parts[i+1], 10, 64)
+		return value, err == nil
+	}
+
+	for i := 0; i < len(parts); i++ {
+		var target *int64
+		switch parts[i] {
+		case "depth":
+			target = &info.Depth
+		case "seldepth":
+			target = &info.SelDepth
+		case "nodes":
+			target = &info.Nodes
+		case "nps":
+			target = &info.NPS
+		case "time":
+			target = &info.Time
+		case "hashfull":
+			target = &info.HashFull
+		case "tbhits":
+			target = &info.TBHits
+		case "multipv":
+			if value, ok := int64At(i); ok {
+				info.MultiPV = int(value)
+				i++
+			}
+		case "score":
+			if i+2 >= len(parts) {
+				continue
+			}
+			value, err := strconv.Atoi(parts[i+2])
+			if err != nil {
+				continue
+			}
+			switch parts[i+1] {
+			case "cp":
+				info.Score, info.HasScore = value, true
+			case "mate":
+				info.IsMate, info.MateIn, info.HasScore = true, value, true
+			default:
+				continue
+			}
+			i += 2
+		case "lowerbound":
+			info.LowerBound = true
+		case "upperbound":
+			info.UpperBound = true
+		case "pv":
+			// Everything after "pv" is the principal variation
+			info.PV = append([]string(nil), parts[i+1:]...)
+			return info
+		case "string":
+			return info
+		}
+
+		if target != nil {
+			if value, ok := int64At(i); ok {
+				*target = value
+				i++
+			}
+		}
+	}
+
+	return info
+}
+
+// exactScore reports whether an info line carries an exact (not bound) score
+func exactScore(info InfoLine) bool {
+	return info.HasScore && !info.LowerBound && !info.UpperBound
+}
+
+// Centipawns returns the score in centipawns, encoding mate scores with MateToScore
+func (info InfoLine) Centipawns() int {
+	if info.IsMate {
+		return MateToScore(info.MateIn)
+	}
+	return info.Score
+}
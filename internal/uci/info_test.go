@@ -0,0 +1,80 @@
+package uci
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInfoLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want InfoLine
+	}{
+		{
+			"centipawn score",
+			"info depth 12 seldepth 18 multipv 1 score cp 34 nodes 52000 nps 1040000 time 50 pv e2e4 e7e5",
+			InfoLine{Depth: 12, SelDepth: 18, MultiPV: 1, Score: 34, HasScore: true, Nodes: 52000, NPS: 1040000, Time: 50, PV: []string{"e2e4", "e7e5"}},
+		},
+		{
+			"getting mated",
+			"info depth 20 score mate -3 pv e1f1 d8d1",
+			InfoLine{Depth: 20, MultiPV: 1, IsMate: true, MateIn: -3, HasScore: true, PV: []string{"e1f1", "d8d1"}},
+		},
+		{
+			"lower bound",
+			"info depth 15 score cp 120 lowerbound nodes 9000",
+			InfoLine{Depth: 15, MultiPV: 1, Score: 120, HasScore: true, LowerBound: true, Nodes: 9000},
+		},
+		{
+			"upper bound",
+			"info depth 15 score cp -40 upperbound",
+			InfoLine{Depth: 15, MultiPV: 1, Score: -40, HasScore: true, UpperBound: true},
+		},
+		{
+			"currmove update has no score",
+			"info depth 22 currmove g1f3 currmovenumber 4",
+			InfoLine{Depth: 22, MultiPV: 1},
+		},
+		{
+			"hashfull and tbhits",
+			"info depth 30 hashfull 512 tbhits 77 score cp 0",
+			InfoLine{Depth: 30, MultiPV: 1, HashFull: 512, TBHits: 77, Score: 0, HasScore: true},
+		},
+		{
+			"empty pv",
+			"info depth 1 score cp 5 pv",
+			InfoLine{Depth: 1, MultiPV: 1, Score: 5, HasScore: true},
+		},
+		{
+			"fields in another order",
+			"info multipv 3 score cp -15 time 8 depth 9 nodes 400 pv d2d4",
+			InfoLine{Depth: 9, MultiPV: 3, Score: -15, HasScore: true, Time: 8, Nodes: 400, PV: []string{"d2d4"}},
+		},
+		{
+			"unknown tokens are skipped",
+			"info depth 7 wdl 500 400 100 refutation e2e4 score cp 21 acpl 3 pv c2c4",
+			InfoLine{Depth: 7, MultiPV: 1, Score: 21, HasScore: true, PV: []string{"c2c4"}},
+		},
+		{
+			"malformed values are skipped",
+			"info depth x nodes 10 score cp y time 3",
+			InfoLine{MultiPV: 1, Nodes: 10, Time: 3},
+		},
+		{
+			"info string ends the line",
+			"info depth 3 string NNUE evaluation using nn.nnue depth 99",
+			InfoLine{Depth: 3, MultiPV: 1},
+		},
+	}
+
+	for _, test := range tests {
+		got := ParseInfoLine(test.line)
+		if len(got.PV) == 0 {
+			got.PV = nil
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ParseInfoLine(%q) =\n%+v, want\n%+v", test.name, test.line, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package uci
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"time"
+)
+
+// EnsureAlive restarts a crashed engine, doubling the delay between attempts
+const (
+	restartInitialBackoff = 100 * time.Millisecond
+	restartMaxBackoff     = 30 * time.Second
+	restartMaxRetries     = 5
+)
+
+// waitForExit reaps the engine process in the background and returns a channel closed once
+// it has exited, after which cmd.ProcessState is set
+func waitForExit(cmd *exec.Cmd) chan struct{} {
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	return exited
+}
+
+// exitedChan returns the channel closed once the current engine process has exited
+func (e *Engine) exitedChan() chan struct{} {
+	e.liveness.Lock()
+	defer e.liveness.Unlock()
+	return e.exited
+}
+
+// isReady reports whether the engine answered the initialization handshake and hasn't
+// failed since
+func (e *Engine) isReady() bool {
+	e.liveness.Lock()
+	defer e.liveness.Unlock()
+	return e.ready
+}
+
+// setReady records whether the engine is ready for commands
+func (e *Engine) setReady(ready bool) {
+	e.liveness.Lock()
+	e.ready = ready
+	e.liveness.Unlock()
+}
+
+// hasExited reports whether the engine process has exited
+func (e *Engine) hasExited() bool {
+	select {
+	case <-e.exitedChan():
+		return true
+	default:
+		return false
+	}
+}
+
+// EnsureAlive restarts the engine from enginePath if its process has exited (crash, OOM kill),
+// retrying with exponential backoff. When every retry fails the engine stays not ready, and
+// EnsureAlive keeps failing, until Restart is called.
+func (e *Engine) EnsureAlive(enginePath string) error {
+	if e.failed {
+		return fmt.Errorf("engine is down: restart failed after %d attempts", restartMaxRetries)
+	}
+	if !e.hasExited() {
+		return nil
+	}
+
+	reason := e.cmd.ProcessState.String()
+	backoff := restartInitialBackoff
+	var err error
+	for attempt := 1; attempt <= restartMaxRetries; attempt++ {
+		slog.Warn("Restarting engine", "path", enginePath, "reason", reason, "attempt", attempt)
+
+		if err = e.Restart(enginePath); err == nil && e.isReady() {
+			return nil
+		}
+		if err == nil {
+			err = fmt.Errorf("engine did not become ready")
+		}
+		reason = err.Error()
+
+		if attempt < restartMaxRetries {
+			time.Sleep(backoff)
+			backoff = min(backoff*2, restartMaxBackoff)
+		}
+	}
+
+	e.setReady(false)
+	e.failed = true
+	return fmt.Errorf("engine restart failed after %d attempts: %v", restartMaxRetries, err)
+}
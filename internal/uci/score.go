@@ -0,0 +1,33 @@
+package uci
+
+// Mate scores are expressed in centipawns as MateScore minus the plies to mate, so that
+// faster mates score higher and every mate outranks any material evaluation
+const (
+	MateScore  = 10000
+	MatedScore = -MateScore
+)
+
+// IsMateScore reports whether a centipawn score encodes a forced mate
+func IsMateScore(score int) bool {
+	return abs(score) > 9000
+}
+
+// MateInMoves returns the number of moves to mate encoded in a mate score
+func MateInMoves(score int) int {
+	return (MateScore - abs(score) + 1) / 2
+}
+
+// MateToScore converts a UCI "score mate N" (moves, negative when being mated) to a centipawn score
+func MateToScore(mateIn int) int {
+	if mateIn > 0 {
+		return MateScore - (2*mateIn - 1)
+	}
+	return MatedScore - 2*mateIn
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
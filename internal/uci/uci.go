@@ -3,18 +3,32 @@ package uci
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// EvaluationDepth is the search depth used by GetEvaluation for quick position evaluations
+const EvaluationDepth = 1
+
 // Engine represents a UCI chess engine (Stockfish)
 type Engine struct {
-	cmd    *exec.Cmd
-	stdin  *bufio.Writer
-	stdout *bufio.Scanner
-	ready  bool
+	cmd     *exec.Cmd
+	stdin   *bufio.Writer
+	stdout  *bufio.Scanner
+	options map[string]bool // Option names advertised by the engine during initialization
+	failed  bool            // Set when EnsureAlive gave up restarting; cleared by Restart
+	threads int             // Threads set with SetThreads, restored on restart (0 = engine default)
+	hashMB  int             // Hash size set with SetHashMB, restored on restart (0 = engine default)
+
+	// The liveness state is guarded by its own lock, since IsAlive is called from health
+	// checks while another goroutine may be restarting the engine
+	liveness sync.Mutex
+	ready    bool
+	exited   chan struct{} // Closed once the engine process has exited
 }
 
 // EngineMove represents a move from the engine
@@ -29,10 +43,19 @@ type EngineMove struct {
 	PVAlgebraic []string // Principal variation in algebraic notation
 }
 
+// MoveEval is the engine's evaluation of one candidate move
+type MoveEval struct {
+	Move  string   // Candidate move in UCI format
+	Score int      // Score after the move for the side to move, mates encoded with MateToScore
+	Depth int      // Search depth
+	PV    []string // Principal variation starting with the move
+}
+
 // MultiPVLine represents one line of analysis in multi-pv mode
 type MultiPVLine struct {
 	LineNumber    int      // Which line this is (1, 2, 3, etc.)
-	Score         int      // Score for this line
+	Score         int      // Score for this line, mates encoded with MateToScore
+	Mate          int      // Moves to mate for this line (negative if getting mated, 0 if none)
 	Depth         int      // Search depth
 	PV            []string // Principal variation in UCI format
 	PVAlgebraic   []string // Principal variation in algebraic notation
@@ -61,7 +84,7 @@ func NewEngine(enginePath string) (*Engine, error) {
 		cmd:    cmd,
 		stdin:  bufio.NewWriter(stdin),
 		stdout: bufio.NewScanner(stdout),
-		ready:  false,
+		exited: waitForExit(cmd),
 	}
 
 	// Initialize the engine
@@ -80,12 +103,20 @@ func (e *Engine) initialize() error {
 		return err
 	}
 
-	// Wait for uciok response
+	// Wait for uciok response, recording the advertised options
+	e.options = make(map[string]bool)
 	for e.stdout.Scan() {
 		line := strings.TrimSpace(e.stdout.Text())
 		if line == "uciok" {
 			break
 		}
+		if strings.HasPrefix(line, "option name ") {
+			name := strings.TrimPrefix(line, "option name ")
+			if i := strings.Index(name, " type "); i >= 0 {
+				name = name[:i]
+			}
+			e.options[name] = true
+		}
 	}
 
 	// Send isready and wait for readyok
@@ -96,7 +127,7 @@ func (e *Engine) initialize() error {
 	for e.stdout.Scan() {
 		line := strings.TrimSpace(e.stdout.Text())
 		if line == "readyok" {
-			e.ready = true
+			e.setReady(true)
 			break
 		}
 	}
@@ -107,18 +138,15 @@ func (e *Engine) initialize() error {
 // sendCommand sends a command to the engine
 func (e *Engine) sendCommand(command string) error {
 	// Check if engine process is still alive
-	if e.cmd.Process != nil {
-		// Try to check if process is still running
-		if e.cmd.ProcessState != nil && e.cmd.ProcessState.Exited() {
-			e.ready = false
-			return fmt.Errorf("engine process has exited")
-		}
+	if e.hasExited() {
+		e.setReady(false)
+		return fmt.Errorf("engine process has exited")
 	}
 
 	if _, err := e.stdin.WriteString(command + "\n"); err != nil {
 		// Only mark as not ready for serious communication failures
 		if strings.Contains(err.Error(), "broken pipe") || strings.Contains(err.Error(), "closed pipe") {
-			e.ready = false
+			e.setReady(false)
 		}
 		return fmt.Errorf("failed to write command '%s': %v", command, err)
 	}
@@ -126,7 +154,7 @@ func (e *Engine) sendCommand(command string) error {
 	if err := e.stdin.Flush(); err != nil {
 		// Only mark as not ready for serious communication failures
 		if strings.Contains(err.Error(), "broken pipe") || strings.Contains(err.Error(), "closed pipe") {
-			e.ready = false
+			e.setReady(false)
 		}
 		return fmt.Errorf("failed to flush command '%s': %v", command, err)
 	}
@@ -136,7 +164,7 @@ func (e *Engine) sendCommand(command string) error {
 
 // SetPosition sets the current position using FEN notation
 func (e *Engine) SetPosition(fen string) error {
-	if !e.ready {
+	if !e.isReady() {
 		return fmt.Errorf("engine not ready")
 	}
 
@@ -155,7 +183,7 @@ func (e *Engine) SetPosition(fen string) error {
 
 // SetPositionWithMoves sets position from start with move history
 func (e *Engine) SetPositionWithMoves(moves []string) error {
-	if !e.ready {
+	if !e.isReady() {
 		return fmt.Errorf("engine not ready")
 	}
 
@@ -168,7 +196,15 @@ func (e *Engine) SetPositionWithMoves(moves []string) error {
 
 // GetBestMove asks the engine for the best move with optional depth
 func (e *Engine) GetBestMove(fen string, depth int) (*EngineMove, error) {
-	if !e.ready {
+	return e.GetBestMoveWithLimits(fen, depth, 0)
+}
+
+// GetBestMoveWithLimits asks the engine for the best move with optional depth and time limit (in ms)
+func (e *Engine) GetBestMoveWithLimits(fen string, depth int, moveTimeMs int) (*EngineMove, error) {
+	if err := e.EnsureAlive(e.Path()); err != nil {
+		return nil, err
+	}
+	if !e.isReady() {
 		return nil, fmt.Errorf("engine not ready")
 	}
 
@@ -182,6 +218,9 @@ func (e *Engine) GetBestMove(fen string, depth int) (*EngineMove, error) {
 	if depth > 0 {
 		command += fmt.Sprintf(" depth %d", depth)
 	}
+	if moveTimeMs > 0 {
+		command += fmt.Sprintf(" movetime %d", moveTimeMs)
+	}
 	if err := e.sendCommand(command); err != nil {
 		return nil, err
 	}
@@ -196,21 +235,12 @@ func (e *Engine) GetBestMove(fen string, depth int) (*EngineMove, error) {
 
 		// Parse info lines for score information and principal variation
 		if strings.HasPrefix(line, "info") {
-			parts := strings.Fields(line)
-			for i, part := range parts {
-				if part == "score" && i+2 < len(parts) {
-					if parts[i+1] == "cp" { // centipawn score
-						if score, err := strconv.Atoi(parts[i+2]); err == nil {
-							lastScore = score
-						}
-					}
-				}
-				// Capture principal variation
-				if part == "pv" && i+1 < len(parts) {
-					// Everything after "pv" is the principal variation
-					lastPV = parts[i+1:]
-					break
-				}
+			info := ParseInfoLine(line)
+			if exactScore(info) {
+				lastScore = info.Centipawns()
+			}
+			if len(info.PV) > 0 {
+				lastPV = info.PV
 			}
 		}
 
@@ -260,14 +290,14 @@ func (e *Engine) Close() error {
 		// Give engine time to quit gracefully
 		time.Sleep(100 * time.Millisecond)
 		e.cmd.Process.Kill()
-		e.cmd.Wait()
+		<-e.exitedChan()
 	}
 	return nil
 }
 
 // Quit sends quit command to engine
 func (e *Engine) Quit() error {
-	if e.ready {
+	if e.isReady() {
 		return e.sendCommand("quit")
 	}
 	return nil
@@ -275,7 +305,7 @@ func (e *Engine) Quit() error {
 
 // SetOption sets a UCI option (like Skill Level or UCI_Elo)
 func (e *Engine) SetOption(name, value string) error {
-	if !e.ready {
+	if !e.isReady() {
 		return fmt.Errorf("engine not ready")
 	}
 
@@ -283,6 +313,44 @@ func (e *Engine) SetOption(name, value string) error {
 	return e.sendCommand(command)
 }
 
+// HasOption reports whether the engine advertised the named UCI option
+func (e *Engine) HasOption(name string) bool {
+	return e.options[name]
+}
+
+// SetContempt sets the engine's draw contempt in centipawns (positive avoids draws).
+// Newer Stockfish versions removed the option, so callers should check HasOption("Contempt").
+func (e *Engine) SetContempt(contempt int) error {
+	if !e.HasOption("Contempt") {
+		return fmt.Errorf("engine does not support the Contempt option")
+	}
+	return e.SetOption("Contempt", fmt.Sprintf("%d", contempt))
+}
+
+// SetThreads sets the number of search threads. The setting survives engine restarts.
+func (e *Engine) SetThreads(n int) error {
+	if n < 1 {
+		return fmt.Errorf("threads must be at least 1")
+	}
+	if err := e.SetOption("Threads", strconv.Itoa(n)); err != nil {
+		return err
+	}
+	e.threads = n
+	return nil
+}
+
+// SetHashMB sets the transposition table size in megabytes. The setting survives engine restarts.
+func (e *Engine) SetHashMB(mb int) error {
+	if mb < 1 {
+		return fmt.Errorf("hash size must be at least 1 MB")
+	}
+	if err := e.SetOption("Hash", strconv.Itoa(mb)); err != nil {
+		return err
+	}
+	e.hashMB = mb
+	return nil
+}
+
 // SetSkillLevel sets the Stockfish skill level (0-20, where 20 is maximum strength)
 func (e *Engine) SetSkillLevel(level int) error {
 	if level < 0 || level > 20 {
@@ -293,7 +361,7 @@ func (e *Engine) SetSkillLevel(level int) error {
 
 // SetEloRating sets the engine strength to a specific ELO rating
 func (e *Engine) SetEloRating(elo int) error {
-	if !e.ready {
+	if !e.isReady() {
 		return fmt.Errorf("engine not ready")
 	}
 
@@ -342,7 +410,7 @@ func (e *Engine) SetEloRating(elo int) error {
 
 // DisableStrengthLimit disables ELO limiting for full strength play
 func (e *Engine) DisableStrengthLimit() error {
-	if !e.ready {
+	if !e.isReady() {
 		return fmt.Errorf("engine not ready")
 	}
 
@@ -360,8 +428,73 @@ func (e *Engine) DisableStrengthLimit() error {
 }
 
 // GetEvaluation gets the static evaluation of the current position
+// The score is in centipawns from the perspective of the side to move
 func (e *Engine) GetEvaluation(fen string) (int, error) {
-	if !e.ready {
+	return e.GetEvaluationWithLimits(fen, EvaluationDepth, 0)
+}
+
+// GetStaticEval returns Stockfish's static evaluation ("eval" command) of a position, without
+// searching. Like GetEvaluation the score is in centipawns for the side to move. Positions the
+// engine can't evaluate statically (in check) and engines without "eval" fall back to GetEvaluation.
+// depth is the search depth behind the score: 0 for a static evaluation, EvaluationDepth after
+// a fallback.
+func (e *Engine) GetStaticEval(fen string) (eval int, depth int, err error) {
+	if !e.isReady() {
+		return 0, 0, fmt.Errorf("engine not ready")
+	}
+
+	if err := e.sendCommand(fmt.Sprintf("position fen %s", fen)); err != nil {
+		return 0, 0, err
+	}
+	if err := e.sendCommand("eval"); err != nil {
+		return 0, 0, err
+	}
+	// The eval output has no terminator of its own, so mark its end with a readyok
+	if err := e.sendCommand("isready"); err != nil {
+		return 0, 0, err
+	}
+
+	// The final line reads "Final evaluation       +0.18 (white side) ...", in pawns. Older
+	// Stockfish prints "Total Evaluation" instead and other engines print nothing at all, so
+	// only the readyok ends the output.
+	var finalLine string
+	for e.stdout.Scan() {
+		line := strings.TrimSpace(e.stdout.Text())
+		if line == "readyok" {
+			break
+		}
+		if strings.HasPrefix(line, "Final evaluation") {
+			finalLine = line
+		}
+	}
+
+	fields := strings.Fields(strings.TrimLeft(strings.TrimPrefix(finalLine, "Final evaluation"), ": "))
+	var pawns float64
+	if len(fields) > 0 {
+		pawns, err = strconv.ParseFloat(fields[0], 64)
+	}
+	if len(fields) == 0 || err != nil {
+		eval, err = e.GetEvaluation(fen)
+		if err != nil {
+			return 0, 0, err
+		}
+		return eval, EvaluationDepth, nil
+	}
+
+	eval = int(math.Round(pawns * 100))
+	if fenFields := strings.Fields(fen); len(fenFields) > 1 && fenFields[1] == "b" {
+		eval = -eval
+	}
+	return eval, 0, nil
+}
+
+// GetEvaluationWithLimits evaluates a position with a deeper search, bounded by depth
+// and an optional time limit (in ms). The score is in centipawns for the side to move.
+func (e *Engine) GetEvaluationWithLimits(fen string, depth int, moveTimeMs int) (int, error) {
+	if err := e.EnsureAlive(e.Path()); err != nil {
+		return 0, err
+	}
+	if !e.isReady() {
 		return 0, fmt.Errorf("engine not ready")
 	}
 
@@ -371,7 +504,11 @@ func (e *Engine) GetEvaluation(fen string) (int, error) {
 	}
 
 	// Use a quick search instead of eval command (which might not be available)
-	if err := e.sendCommand("go depth 1"); err != nil {
+	command := fmt.Sprintf("go depth %d", depth)
+	if moveTimeMs > 0 {
+		command += fmt.Sprintf(" movetime %d", moveTimeMs)
+	}
+	if err := e.sendCommand(command); err != nil {
 		return 0, err
 	}
 
@@ -383,15 +520,8 @@ func (e *Engine) GetEvaluation(fen string) (int, error) {
 
 		// Parse info lines for score information
 		if strings.HasPrefix(line, "info") {
-			parts := strings.Fields(line)
-			for i, part := range parts {
-				if part == "score" && i+2 < len(parts) {
-					if parts[i+1] == "cp" { // centipawn score
-						if score, err := strconv.Atoi(parts[i+2]); err == nil {
-							lastScore = score
-						}
-					}
-				}
+			if info := ParseInfoLine(line); exactScore(info) {
+				lastScore = info.Centipawns()
 			}
 		}
 
@@ -404,9 +534,107 @@ func (e *Engine) GetEvaluation(fen string) (int, error) {
 	return lastScore, nil
 }
 
+// GetLegalMoves returns the engine's legal moves for a position in UCI notation, using "go perft 1"
+func (e *Engine) GetLegalMoves(fen string) ([]string, error) {
+	if !e.isReady() {
+		return nil, fmt.Errorf("engine not ready")
+	}
+
+	if err := e.sendCommand(fmt.Sprintf("position fen %s", fen)); err != nil {
+		return nil, err
+	}
+	if err := e.sendCommand("go perft 1"); err != nil {
+		return nil, err
+	}
+
+	// Perft prints one "move: count" line per legal move, then "Nodes searched: N"
+	legalMoves := []string{}
+	for e.stdout.Scan() {
+		line := strings.TrimSpace(e.stdout.Text())
+
+		if strings.HasPrefix(line, "Nodes searched") {
+			return legalMoves, nil
+		}
+		if move, _, found := strings.Cut(line, ":"); found && len(move) >= 4 && len(move) <= 5 {
+			legalMoves = append(legalMoves, move)
+		}
+	}
+
+	return nil, fmt.Errorf("perft output ended unexpectedly")
+}
+
+// BenchmarkResult is the outcome of a fixed-time search, for measuring engine speed
+type BenchmarkResult struct {
+	BestMove       string `json:"bestMove"`
+	MaxDepth       int64  `json:"maxDepth"`
+	TotalNodes     int64  `json:"totalNodes"`
+	NodesPerSecond int64  `json:"nodesPerSecond"`
+	DurationMs     int64  `json:"durationMs"` // Search time reported by the engine
+}
+
+// Benchmark searches a position for moveTimeMs and reports the depth, node count and speed
+// from the engine's last info line
+func (e *Engine) Benchmark(fen string, moveTimeMs int) (*BenchmarkResult, error) {
+	if err := e.EnsureAlive(e.Path()); err != nil {
+		return nil, err
+	}
+	if !e.isReady() {
+		return nil, fmt.Errorf("engine not ready")
+	}
+
+	if err := e.sendCommand(fmt.Sprintf("position fen %s", fen)); err != nil {
+		return nil, err
+	}
+	if err := e.sendCommand(fmt.Sprintf("go movetime %d", moveTimeMs)); err != nil {
+		return nil, err
+	}
+
+	result := &BenchmarkResult{}
+	for e.stdout.Scan() {
+		line := strings.TrimSpace(e.stdout.Text())
+
+		if strings.HasPrefix(line, "info") {
+			info := ParseInfoLine(line)
+			if info.Depth > result.MaxDepth {
+				result.MaxDepth = info.Depth
+			}
+			if info.Nodes > 0 {
+				result.TotalNodes = info.Nodes
+				result.NodesPerSecond = info.NPS
+				result.DurationMs = info.Time
+			}
+		}
+
+		if strings.HasPrefix(line, "bestmove") {
+			if parts := strings.Fields(line); len(parts) >= 2 {
+				result.BestMove = parts[1]
+				return result, nil
+			}
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("no best move found")
+}
+
+// update sets the line from a newer info line of the search. Score is always replaced, so a
+// line that turns from a centipawn score into a mate doesn't keep its old score.
+func (l *MultiPVLine) update(info InfoLine) {
+	l.Depth = int(info.Depth)
+	l.PV = info.PV
+	l.Score = info.Centipawns()
+	l.Mate = 0
+	if info.IsMate {
+		l.Mate = info.MateIn
+	}
+}
+
 // GetMultiPVAnalysis gets multiple principal variations from the engine
 func (e *Engine) GetMultiPVAnalysis(fen string, depth int, numLines int) ([]MultiPVLine, error) {
-	if !e.ready {
+	if err := e.EnsureAlive(e.Path()); err != nil {
+		return nil, err
+	}
+	if !e.isReady() {
 		return nil, fmt.Errorf("engine not ready")
 	}
 
@@ -435,53 +663,24 @@ func (e *Engine) GetMultiPVAnalysis(fen string, depth int, numLines int) ([]Mult
 	}
 
 	lines := make(map[int]*MultiPVLine)
-	var maxDepth int
 
 	// Read the search output
 	for e.stdout.Scan() {
 		line := strings.TrimSpace(e.stdout.Text())
 
 		// Parse info lines for multiple PV information
+		// Only lines carrying an exact score and a PV describe a PV line; currmove and
+		// bound updates are skipped
 		if strings.HasPrefix(line, "info") {
-			parts := strings.Fields(line)
-			var currentLine *MultiPVLine
-
-			for i, part := range parts {
-				// Find which PV line this is
-				if part == "multipv" && i+1 < len(parts) {
-					lineNum, err := strconv.Atoi(parts[i+1])
-					if err == nil {
-						if lines[lineNum] == nil {
-							lines[lineNum] = &MultiPVLine{LineNumber: lineNum}
-						}
-						currentLine = lines[lineNum]
-					}
+			info := ParseInfoLine(line)
+			if exactScore(info) && len(info.PV) > 0 {
+				currentLine := lines[info.MultiPV]
+				if currentLine == nil {
+					currentLine = &MultiPVLine{LineNumber: info.MultiPV}
+					lines[info.MultiPV] = currentLine
 				}
 
-				// Get depth
-				if part == "depth" && i+1 < len(parts) && currentLine != nil {
-					if depth, err := strconv.Atoi(parts[i+1]); err == nil {
-						currentLine.Depth = depth
-						if depth > maxDepth {
-							maxDepth = depth
-						}
-					}
-				}
-
-				// Get score
-				if part == "score" && i+2 < len(parts) && currentLine != nil {
-					if parts[i+1] == "cp" {
-						if score, err := strconv.Atoi(parts[i+2]); err == nil {
-							currentLine.Score = score
-						}
-					}
-				}
-
-				// Get principal variation
-				if part == "pv" && i+1 < len(parts) && currentLine != nil {
-					currentLine.PV = parts[i+1:]
-					break
-				}
+				currentLine.update(info)
 			}
 		}
 
@@ -505,9 +704,81 @@ func (e *Engine) GetMultiPVAnalysis(fen string, depth int, numLines int) ([]Mult
 	return result, nil
 }
 
+// AnalyzeMoveCandidates evaluates only the given moves of a position, best first. The search is
+// restricted to the candidates with "searchmoves" and runs one MultiPV line per candidate;
+// candidates that are not legal in the position are left out of the result.
+func (e *Engine) AnalyzeMoveCandidates(fen string, candidates []string, depth int) ([]MoveEval, error) {
+	if err := e.EnsureAlive(e.Path()); err != nil {
+		return nil, err
+	}
+	if !e.isReady() {
+		return nil, fmt.Errorf("engine not ready")
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no candidate moves")
+	}
+
+	if err := e.SetOption("MultiPV", strconv.Itoa(len(candidates))); err != nil {
+		return nil, fmt.Errorf("failed to set MultiPV: %v", err)
+	}
+	// Reset MultiPV to 1 for other operations
+	defer e.SetOption("MultiPV", "1")
+
+	if err := e.sendCommand(fmt.Sprintf("position fen %s", fen)); err != nil {
+		return nil, fmt.Errorf("failed to set position: %v", err)
+	}
+
+	command := "go"
+	if depth > 0 {
+		command += fmt.Sprintf(" depth %d", depth)
+	}
+	command += " searchmoves " + strings.Join(candidates, " ")
+	if err := e.sendCommand(command); err != nil {
+		return nil, err
+	}
+
+	isCandidate := make(map[string]bool, len(candidates))
+	for _, move := range candidates {
+		isCandidate[move] = true
+	}
+
+	// Keep the latest exact-score line of each MultiPV slot that starts with a candidate
+	lines := make(map[int]InfoLine)
+	for e.stdout.Scan() {
+		line := strings.TrimSpace(e.stdout.Text())
+
+		if strings.HasPrefix(line, "info") {
+			info := ParseInfoLine(line)
+			if exactScore(info) && len(info.PV) > 0 && isCandidate[info.PV[0]] {
+				lines[info.MultiPV] = info
+			}
+		}
+
+		if strings.HasPrefix(line, "bestmove") {
+			break
+		}
+	}
+
+	// A move can only appear once, in its best slot
+	evals := make([]MoveEval, 0, len(lines))
+	for i := 1; i <= len(candidates); i++ {
+		if info, exists := lines[i]; exists && isCandidate[info.PV[0]] {
+			isCandidate[info.PV[0]] = false
+			evals = append(evals, MoveEval{
+				Move:  info.PV[0],
+				Score: info.Centipawns(),
+				Depth: int(info.Depth),
+				PV:    info.PV,
+			})
+		}
+	}
+
+	return evals, nil
+}
+
 // GetEngineInfo gets the Stockfish engine information including version
 func (e *Engine) GetEngineInfo() (string, error) {
-	if !e.ready {
+	if !e.isReady() {
 		return "", fmt.Errorf("engine not ready")
 	}
 
@@ -538,9 +809,7 @@ func (e *Engine) GetEngineInfo() (string, error) {
 
 // IsAlive checks if the engine process is still running and responsive
 func (e *Engine) IsAlive() bool {
-	// Simplified check - just verify engine is ready
-	// Complex process state checking was causing false negatives
-	return e.ready
+	return e.isReady() && !e.hasExited()
 }
 
 // Ping sends an isready command to check if engine is responsive
@@ -553,12 +822,17 @@ func (e *Engine) Ping() error {
 	return e.sendCommand("isready")
 }
 
+// Path returns the executable the engine was started from
+func (e *Engine) Path() string {
+	return e.cmd.Path
+}
+
 // Restart recreates the engine process when it crashes or becomes unresponsive
 func (e *Engine) Restart(enginePath string) error {
 	// Close the old engine if it exists
 	if e.cmd != nil && e.cmd.Process != nil {
 		e.cmd.Process.Kill()
-		e.cmd.Wait()
+		<-e.exitedChan()
 	}
 
 	// Create new engine process
@@ -582,8 +856,27 @@ func (e *Engine) Restart(enginePath string) error {
 	e.cmd = cmd
 	e.stdin = bufio.NewWriter(stdin)
 	e.stdout = bufio.NewScanner(stdout)
+	e.liveness.Lock()
 	e.ready = false
+	e.exited = waitForExit(cmd)
+	e.liveness.Unlock()
+	e.failed = false
 
 	// Initialize the restarted engine
-	return e.initialize()
+	if err := e.initialize(); err != nil {
+		return err
+	}
+
+	// Restore the resources the engine was configured with
+	if e.threads > 0 {
+		if err := e.SetOption("Threads", strconv.Itoa(e.threads)); err != nil {
+			return err
+		}
+	}
+	if e.hashMB > 0 {
+		if err := e.SetOption("Hash", strconv.Itoa(e.hashMB)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,121 @@
+package uci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zully/chess-engine/internal/uci/ucitest"
+)
+
+func TestMultiPVLineUpdateReplacesScore(t *testing.T) {
+	var line MultiPVLine
+
+	line.update(ParseInfoLine("info depth 10 multipv 1 score cp 150 pv e2e4 e7e5"))
+	if line.Score != 150 || line.Mate != 0 {
+		t.Fatalf("after cp info got score %d mate %d, want 150 and 0", line.Score, line.Mate)
+	}
+
+	line.update(ParseInfoLine("info depth 12 multipv 1 score mate 3 pv d1h5"))
+	if line.Mate != 3 {
+		t.Errorf("after mate info got mate %d, want 3", line.Mate)
+	}
+	if line.Score != MateToScore(3) {
+		t.Errorf("after mate info got score %d, want %d", line.Score, MateToScore(3))
+	}
+	if line.Depth != 12 || len(line.PV) != 1 || line.PV[0] != "d1h5" {
+		t.Errorf("after mate info got depth %d pv %v", line.Depth, line.PV)
+	}
+
+	line.update(ParseInfoLine("info depth 13 multipv 1 score cp -40 pv d1h5 g7g6"))
+	if line.Score != -40 || line.Mate != 0 {
+		t.Errorf("after cp info got score %d mate %d, want -40 and 0", line.Score, line.Mate)
+	}
+}
+
+// startFakeEngine starts a scripted engine, closed when the test ends
+func startFakeEngine(t *testing.T, config ucitest.Config) *Engine {
+	t.Helper()
+	engine, err := NewEngine(ucitest.Path(t, config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { engine.Close() })
+	return engine
+}
+
+func TestGetStaticEval(t *testing.T) {
+	// White is a knight up with Black to move; the fake engine's static evaluation adds a
+	// small bonus for the side to move that its search scores don't have
+	const knightUp = "4k3/8/8/8/8/8/8/3NK3 b - - 0 1"
+	const inCheck = "4k3/8/8/8/8/8/4R3/4K3 b - - 0 1"
+
+	tests := []struct {
+		name      string
+		eval      string
+		fen       string
+		want      int
+		wantDepth int
+	}{
+		{"final evaluation", ucitest.EvalFinal, knightUp, -(320 - ucitest.StaticEvalBonus), 0},
+		{"in check falls back to a search", ucitest.EvalFinal, inCheck, -500, EvaluationDepth},
+		{"total evaluation falls back to a search", ucitest.EvalTotal, knightUp, -320, EvaluationDepth},
+		{"eval not supported falls back to a search", ucitest.EvalNone, knightUp, -320, EvaluationDepth},
+	}
+
+	for _, tt := range tests {
+		engine := startFakeEngine(t, ucitest.Config{Eval: tt.eval})
+
+		type result struct {
+			eval  int
+			depth int
+			err   error
+		}
+		done := make(chan result, 1)
+		go func() {
+			eval, depth, err := engine.GetStaticEval(tt.fen)
+			done <- result{eval, depth, err}
+		}()
+
+		select {
+		case r := <-done:
+			if r.err != nil {
+				t.Errorf("%s: %v", tt.name, r.err)
+			} else if r.eval != tt.want || r.depth != tt.wantDepth {
+				t.Errorf("%s: got %d at depth %d, want %d at depth %d", tt.name, r.eval, r.depth, tt.want, tt.wantDepth)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: GetStaticEval did not return", tt.name)
+		}
+	}
+}
+
+func TestIsAliveDuringRestart(t *testing.T) {
+	engine := startFakeEngine(t, ucitest.Config{})
+
+	// Health checks call IsAlive without the lock the restarting caller holds
+	stop := make(chan struct{})
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				engine.IsAlive()
+			}
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		if err := engine.Restart(engine.Path()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	<-checked
+
+	if !engine.IsAlive() {
+		t.Error("engine not alive after restarting")
+	}
+}
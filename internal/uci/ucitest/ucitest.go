@@ -0,0 +1,344 @@
+// Package ucitest provides a scripted UCI engine for tests that need an engine process but
+// not Stockfish. The engine runs inside the test binary: a test package calls Main from its
+// TestMain, and Path returns an executable path that starts the binary as an engine.
+//
+// The engine plays greedily by material and reports material scores, so its output is
+// deterministic and easy to predict from the position.
+package ucitest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// Output of the "eval" command
+const (
+	EvalFinal = "final" // "Final evaluation +0.10 (white side)", as Stockfish 12 and later print
+	EvalTotal = "total" // "Total Evaluation: 0.10 (white side)", as older Stockfish prints
+	EvalNone  = "none"  // Nothing, as engines without the command do
+)
+
+// StaticEvalBonus is added, for the side to move, to the material in the "eval" output so
+// tests can tell a static evaluation from a search score
+const StaticEvalBonus = 10
+
+// Config scripts the behaviour of a fake engine
+type Config struct {
+	Name      string   // Reported with "id name"
+	Options   []string // Option names advertised during "uci"
+	Eval      string   // Output of "eval": EvalFinal (the default), EvalTotal or EvalNone
+	PerftSkip string   // Move left out of "go perft 1", to make the legal moves disagree
+	PVLength  int      // Moves in each reported PV (default 3)
+}
+
+// Path returns a path that starts the test binary as a fake engine with the given config.
+// The test package must call Main from TestMain.
+func Path(t testing.TB, config Config) string {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := config.Name
+	if name == "" {
+		name = "engine"
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.Symlink(executable, path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".json", data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Main runs the fake engine and exits when the test binary was started through a path from
+// Path, and otherwise returns so the tests run
+func Main() {
+	data, err := os.ReadFile(os.Args[0] + ".json")
+	if err != nil {
+		return
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		fmt.Fprintln(os.Stderr, "ucitest:", err)
+		os.Exit(2)
+	}
+	Run(config, os.Stdin, os.Stdout)
+	os.Exit(0)
+}
+
+// Run plays the engine side of the UCI protocol on in and out until "quit" or the end of input
+func Run(config Config, in io.Reader, out io.Writer) {
+	e := &engine{config: config, out: bufio.NewWriter(out), position: board.NewBoard(), multiPV: 1}
+	if e.config.Name == "" {
+		e.config.Name = "ucitest"
+	}
+	if e.config.Eval == "" {
+		e.config.Eval = EvalFinal
+	}
+	if e.config.PVLength <= 0 {
+		e.config.PVLength = 3
+	}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" {
+			return
+		}
+		e.command(fields)
+		e.out.Flush()
+	}
+}
+
+// engine is the state of a running fake engine
+type engine struct {
+	config   Config
+	out      *bufio.Writer
+	position *board.Board
+	multiPV  int
+}
+
+// command answers one line of input
+func (e *engine) command(fields []string) {
+	switch fields[0] {
+	case "uci":
+		e.printf("id name %s", e.config.Name)
+		e.printf("id author ucitest")
+		for _, option := range e.config.Options {
+			e.printf("option name %s type string default <empty>", option)
+		}
+		e.printf("uciok")
+	case "isready":
+		e.printf("readyok")
+	case "setoption":
+		// setoption name MultiPV value N
+		if len(fields) >= 5 && fields[2] == "MultiPV" {
+			if n, err := strconv.Atoi(fields[4]); err == nil && n > 0 {
+				e.multiPV = n
+			}
+		}
+	case "position":
+		e.setPosition(fields[1:])
+	case "eval":
+		e.eval()
+	case "go":
+		e.goCommand(fields[1:])
+	}
+}
+
+func (e *engine) printf(format string, args ...interface{}) {
+	fmt.Fprintf(e.out, format+"\n", args...)
+}
+
+// setPosition handles "position startpos|fen <fen> [moves ...]"
+func (e *engine) setPosition(args []string) {
+	moves := []string{}
+	for i, arg := range args {
+		if arg == "moves" {
+			moves = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	position := board.NewBoard()
+	if len(args) > 1 && args[0] == "fen" {
+		b, err := board.FromFEN(strings.Join(args[1:], " "))
+		if err != nil {
+			e.printf("info string invalid fen: %v", err)
+			return
+		}
+		position = b
+	}
+	if _, err := position.ApplyMoves(moves); err != nil {
+		e.printf("info string invalid moves: %v", err)
+		return
+	}
+	e.position = position
+}
+
+// eval prints the static evaluation in the configured format
+func (e *engine) eval() {
+	pawns := float64(e.position.GetMaterialBalance()+StaticEvalBonus*sideSign(e.position)) / 100
+	switch e.config.Eval {
+	case EvalFinal:
+		if e.position.IsInCheck(e.position.SideToMove()) {
+			e.printf("Final evaluation: none (in check)")
+			return
+		}
+		e.printf("Final evaluation       %+.2f (white side)", pawns)
+	case EvalTotal:
+		e.printf("Total Evaluation: %.2f (white side)", pawns)
+	}
+}
+
+// goCommand handles "go perft 1" and searches with "depth", "movetime" and "searchmoves"
+func (e *engine) goCommand(args []string) {
+	if len(args) >= 1 && args[0] == "perft" {
+		e.perft()
+		return
+	}
+
+	depth := 1
+	var searchMoves []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "depth":
+			if i+1 < len(args) {
+				if n, err := strconv.Atoi(args[i+1]); err == nil {
+					depth = n
+				}
+				i++
+			}
+		case "searchmoves":
+			searchMoves = args[i+1:]
+			i = len(args)
+		}
+	}
+
+	candidates := e.position.GetLegalMoves()
+	if len(searchMoves) > 0 {
+		allowed := make(map[string]bool)
+		for _, move := range searchMoves {
+			allowed[move] = true
+		}
+		var restricted []string
+		for _, move := range candidates {
+			if allowed[move] {
+				restricted = append(restricted, move)
+			}
+		}
+		candidates = restricted
+	}
+
+	if len(candidates) == 0 {
+		if e.position.IsInCheck(e.position.SideToMove()) {
+			e.printf("info depth 0 score mate 0")
+		} else {
+			e.printf("info depth 0 score cp 0")
+		}
+		e.printf("bestmove (none)")
+		return
+	}
+
+	lines := rankMoves(e.position, candidates)
+	if len(lines) > e.multiPV {
+		lines = lines[:e.multiPV]
+	}
+	for i, first := range lines {
+		pv, score := e.principalVariation(first)
+		e.printf("info depth %d seldepth %d multipv %d score %s nodes 1000 nps 100000 time 10 pv %s",
+			depth, depth, i+1, score, strings.Join(pv, " "))
+	}
+	e.printf("bestmove %s", lines[0])
+}
+
+// principalVariation plays the greedy line starting with first and returns it with its UCI
+// score for the side to move at the root
+func (e *engine) principalVariation(first string) ([]string, string) {
+	replay := e.position.Clone()
+	root := replay.SideToMove()
+
+	pv := []string{first}
+	replay.MakeUCIMove(first)
+	for len(pv) < e.config.PVLength {
+		legalMoves := replay.GetLegalMoves()
+		if len(legalMoves) == 0 {
+			break
+		}
+		move := rankMoves(replay, legalMoves)[0]
+		replay.MakeUCIMove(move)
+		pv = append(pv, move)
+	}
+
+	if replay.IsCheckmate(replay.SideToMove()) {
+		mateIn := (len(pv) + 1) / 2
+		if replay.SideToMove() == root {
+			mateIn = -mateIn
+		}
+		return pv, fmt.Sprintf("mate %d", mateIn)
+	}
+
+	score := replay.GetMaterialBalance()
+	if root == board.Black {
+		score = -score
+	}
+	return pv, fmt.Sprintf("cp %d", score)
+}
+
+// perft prints the legal moves as "go perft 1" does, leaving out the configured move
+func (e *engine) perft() {
+	legalMoves := e.position.GetLegalMoves()
+	sort.Strings(legalMoves)
+
+	nodes := 0
+	for _, move := range legalMoves {
+		if move == e.config.PerftSkip {
+			continue
+		}
+		e.printf("%s: 1", move)
+		nodes++
+	}
+	e.printf("")
+	e.printf("Nodes searched: %d", nodes)
+}
+
+// rankMoves orders moves by the material the mover has after them, best first, mates before
+// anything else and ties in UCI order
+func rankMoves(b *board.Board, moves []string) []string {
+	mover := b.SideToMove()
+	value := make(map[string]int, len(moves))
+	for _, move := range moves {
+		next := b.Clone()
+		if err := next.MakeUCIMove(move); err != nil {
+			continue
+		}
+		value[move] = next.GetMaterialBalance()
+		if mover == board.Black {
+			value[move] = -value[move]
+		}
+		if next.IsCheckmate(next.SideToMove()) {
+			value[move] += 100000
+		}
+	}
+
+	ranked := append([]string(nil), moves...)
+	sort.Slice(ranked, func(i, j int) bool {
+		if value[ranked[i]] != value[ranked[j]] {
+			return value[ranked[i]] > value[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked
+}
+
+// sideSign is 1 when White is to move and -1 when Black is
+func sideSign(b *board.Board) int {
+	if b.WhiteToMove {
+		return 1
+	}
+	return -1
+}
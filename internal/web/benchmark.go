@@ -0,0 +1,81 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+const (
+	// benchmarkMoveTimeMs is how long the engine benchmark searches the starting position
+	benchmarkMoveTimeMs = 5000
+
+	// maxPerftDepth bounds the perft benchmark, which runs on our own move generator
+	maxPerftDepth = 5
+)
+
+// PerftResponse is the response of the perft benchmark endpoint
+type PerftResponse struct {
+	Depth      int   `json:"depth"`
+	Nodes      int64 `json:"nodes"`
+	DurationMs int64 `json:"durationMs"`
+}
+
+// SetupDebugRoutes registers the development endpoints, only wanted when debugging is enabled
+func (s *Server) SetupDebugRoutes(mux Router) {
+	mux.HandleFunc("/api/engine/benchmark", s.EngineBenchmark)
+	mux.HandleFunc("/api/engine/benchmark/perft/", s.PerftBenchmark)
+}
+
+// EngineBenchmark searches the starting position for 5 seconds and reports the engine's speed
+func (s *Server) EngineBenchmark(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.StockfishEngine == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Stockfish engine not available"})
+		return
+	}
+
+	result, err := s.StockfishEngine.Benchmark(board.NewBoard().ToFEN(), benchmarkMoveTimeMs)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Benchmark failed: %v", err)})
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// PerftBenchmark counts the move tree of the starting position to /api/engine/benchmark/perft/{depth}
+func (s *Server) PerftBenchmark(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	depth, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/engine/benchmark/perft/"))
+	if err != nil || depth < 1 || depth > maxPerftDepth {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Depth must be between 1 and %d", maxPerftDepth)})
+		return
+	}
+
+	start := time.Now()
+	nodes := board.NewBoard().Perft(depth)
+	json.NewEncoder(w).Encode(PerftResponse{Depth: depth, Nodes: nodes, DurationMs: time.Since(start).Milliseconds()})
+}
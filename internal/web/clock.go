@@ -0,0 +1,78 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/zully/chess-engine/internal/game"
+)
+
+// ClockConfig sets up a stopped clock for the current game
+func (s *Server) ClockConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	defer s.lockGame()()
+
+	var req ClockConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	if req.White <= 0 || req.Black <= 0 || req.Increment < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Times must be positive and the increment not negative"})
+		return
+	}
+
+	s.Clock = game.NewGameClock(req.White, req.Black, req.Increment)
+	json.NewEncoder(w).Encode(s.Clock.State())
+}
+
+// StartClock starts the clock of the side to move
+func (s *Server) StartClock(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	defer s.lockGame()()
+
+	if s.Clock == nil {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Clock not configured"})
+		return
+	}
+	if s.Clock.CheckFlag() {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Time is up"})
+		return
+	}
+
+	if !s.Clock.Running {
+		s.Clock.Start(s.GameBoard.WhiteToMove)
+	}
+	json.NewEncoder(w).Encode(s.Clock.State())
+}
+
+// clockMoveMade charges the side that just moved on the game clock, if there is one
+func (s *Server) clockMoveMade() {
+	if s.Clock != nil {
+		s.Clock.OnMoveMade(!s.GameBoard.WhiteToMove)
+	}
+}
+
+// clockFollowSideToMove switches the running clock to the side to move, e.g. after an undo
+func (s *Server) clockFollowSideToMove() {
+	if s.Clock != nil && s.Clock.Running && s.Clock.ActiveColor != s.GameBoard.WhiteToMove {
+		s.Clock.Stop()
+		s.Clock.Start(s.GameBoard.WhiteToMove)
+	}
+}
@@ -0,0 +1,57 @@
+package web
+
+import (
+	"log/slog"
+	"sort"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// checkLegality compares our legal move list for a position with Stockfish's and logs
+// any difference. It does nothing unless LegalityCheck is enabled.
+func (s *Server) checkLegality(position *board.Board) {
+	if !s.LegalityCheck || s.StockfishEngine == nil {
+		return
+	}
+
+	fen := position.ToFEN()
+	engineMoves, err := s.StockfishEngine.GetLegalMoves(fen)
+	if err != nil {
+		slog.Warn("legality check failed", "fen", fen, "error", err)
+		return
+	}
+
+	missing, extra := diffMoves(engineMoves, position.GetLegalMoves())
+	if len(missing) > 0 || len(extra) > 0 {
+		slog.Warn("legal move mismatch with Stockfish",
+			"fen", fen,
+			"stockfishCount", len(engineMoves),
+			"missing", missing, // Legal according to Stockfish, not generated by us
+			"extra", extra, // Generated by us, illegal according to Stockfish
+		)
+	}
+}
+
+// diffMoves returns the moves only in want and the moves only in got, sorted
+func diffMoves(want, got []string) (missing, extra []string) {
+	inGot := make(map[string]bool, len(got))
+	for _, move := range got {
+		inGot[move] = true
+	}
+	inWant := make(map[string]bool, len(want))
+	for _, move := range want {
+		inWant[move] = true
+		if !inGot[move] {
+			missing = append(missing, move)
+		}
+	}
+	for _, move := range got {
+		if !inWant[move] {
+			extra = append(extra, move)
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra
+}
@@ -0,0 +1,78 @@
+package web
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
+	"github.com/zully/chess-engine/internal/uci/ucitest"
+)
+
+// captureLogs sends the default logger's output to a buffer until the test ends
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &logs
+}
+
+func TestLegalityCheckLogsMismatches(t *testing.T) {
+	const mismatch = "legal move mismatch"
+
+	post := func(handler http.HandlerFunc, path, body string) {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	}
+
+	t.Run("player move", func(t *testing.T) {
+		// The engine leaves 1.e4 out of its legal moves, so checking the start position warns
+		s := NewServer(board.NewBoard(), startFakeEngine(t, ucitest.Config{PerftSkip: "e2e4"}))
+		defer s.Close()
+		s.LegalityCheck = true
+		logs := captureLogs(t)
+
+		post(s.MakeMove, "/api/move", `{"move":"e2e5"}`)
+		if strings.Contains(logs.String(), mismatch) {
+			t.Errorf("rejected move was checked: %s", logs)
+		}
+
+		s.Clock = &game.GameClock{WhiteMs: 0, BlackMs: 60000, WhiteTimedOut: true}
+		post(s.MakeMove, "/api/move", `{"move":"e2e4"}`)
+		if strings.Contains(logs.String(), mismatch) || len(s.GameBoard.UCIMoves) != 0 {
+			t.Errorf("move after the flag fell was checked or played: %s", logs)
+		}
+		s.Clock = nil
+
+		post(s.MakeMove, "/api/move", `{"move":"e2e4"}`)
+		if !strings.Contains(logs.String(), mismatch) || !strings.Contains(logs.String(), "extra=[e2e4]") {
+			t.Errorf("played move was not checked against the engine: %q", logs)
+		}
+	})
+
+	t.Run("engine move", func(t *testing.T) {
+		// The engine leaves 1...Nf6 out, so only Black's position after 1.e4 warns
+		s := NewServer(board.NewBoard(), startFakeEngine(t, ucitest.Config{PerftSkip: "g8f6"}))
+		defer s.Close()
+		s.LegalityCheck = true
+		logs := captureLogs(t)
+
+		post(s.MakeMove, "/api/move", `{"move":"e2e4"}`)
+		if strings.Contains(logs.String(), mismatch) {
+			t.Errorf("start position reported a mismatch: %s", logs)
+		}
+
+		post(s.EngineMove, "/api/engine/move", "")
+		if len(s.GameBoard.UCIMoves) != 2 {
+			t.Fatalf("engine did not move: %v", s.GameBoard.UCIMoves)
+		}
+		if !strings.Contains(logs.String(), mismatch) || !strings.Contains(logs.String(), "extra=[g8f6]") {
+			t.Errorf("engine move was not checked against the engine: %q", logs)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/zully/chess-engine/internal/uci"
+)
+
+// EngineSpec names a UCI engine binary the game can be switched to
+type EngineSpec struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+}
+
+// EngineStatus describes a configured engine and its health
+type EngineStatus struct {
+	ID      string `json:"id"`
+	Path    string `json:"path"`
+	Active  bool   `json:"active"`  // Engine used by the current game
+	Started bool   `json:"started"` // Engines are started on first use
+	Healthy bool   `json:"healthy"`
+}
+
+// ParseEngineSpecs parses an engine list of the form "stockfish=/usr/local/bin/stockfish,lc0=/usr/bin/lc0"
+func ParseEngineSpecs(value string) ([]EngineSpec, error) {
+	var specs []EngineSpec
+	seen := make(map[string]bool)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, path, found := strings.Cut(entry, "=")
+		id, path = strings.TrimSpace(id), strings.TrimSpace(path)
+		if !found || id == "" || path == "" {
+			return nil, fmt.Errorf("invalid engine %q, expected id=path", entry)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate engine id %q", id)
+		}
+		seen[id] = true
+		specs = append(specs, EngineSpec{ID: id, Path: path})
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no engines configured")
+	}
+	return specs, nil
+}
+
+// SetEngines configures the engines the game can switch between. The first one is the
+// default; the engine passed to NewServer is registered as its running instance.
+func (s *Server) SetEngines(specs []EngineSpec) {
+	s.engineSpecs = specs
+	s.engines = make(map[string]*uci.Engine)
+	if len(specs) == 0 {
+		return
+	}
+
+	if s.StockfishEngine != nil {
+		s.engines[specs[0].ID] = s.StockfishEngine
+	}
+	if s.EngineConfig.EngineID == "" {
+		s.EngineConfig.EngineID = specs[0].ID
+	}
+}
+
+// SetEngineResources sets the search threads and hash size (in MB) of every engine, both
+// those already started and those started later
+func (s *Server) SetEngineResources(threads, hashMB int) {
+	s.engineThreads = threads
+	s.engineHashMB = hashMB
+	for _, engine := range s.engines {
+		s.configureEngine(engine)
+	}
+}
+
+// configureEngine applies the engine resource settings to an engine
+func (s *Server) configureEngine(engine *uci.Engine) {
+	if s.engineThreads > 0 {
+		if err := engine.SetThreads(s.engineThreads); err != nil {
+			slog.Warn("failed to set engine threads", "error", err)
+		}
+	}
+	if s.engineHashMB > 0 {
+		if err := engine.SetHashMB(s.engineHashMB); err != nil {
+			slog.Warn("failed to set engine hash size", "error", err)
+		}
+	}
+	slog.Info("engine configured", "path", engine.Path(), "threads", s.engineThreads, "hashMB", s.engineHashMB)
+}
+
+// engineSpec returns the configured engine with the given ID
+func (s *Server) engineSpec(id string) (EngineSpec, bool) {
+	for _, spec := range s.engineSpecs {
+		if spec.ID == id {
+			return spec, true
+		}
+	}
+	return EngineSpec{}, false
+}
+
+// selectEngine makes the engine with the given ID the active one, starting it on first use
+// The caller must hold s.mu
+func (s *Server) selectEngine(id string) error {
+	spec, exists := s.engineSpec(id)
+	if !exists {
+		return fmt.Errorf("unknown engine %q", id)
+	}
+
+	engine := s.engines[id]
+	if engine == nil || !engine.IsAlive() {
+		started, err := uci.NewEngine(spec.Path)
+		if err != nil {
+			return fmt.Errorf("failed to start engine %q: %v", id, err)
+		}
+		if engine != nil {
+			engine.Close()
+		}
+		engine = started
+		s.engines[id] = engine
+		s.configureEngine(engine)
+	}
+
+	if engine != s.StockfishEngine {
+		s.StockfishEngine = engine
+		s.evalCache = make(map[string]int) // Evaluations differ between engines
+	}
+	return nil
+}
+
+// Close cancels background jobs, stops webhook delivery and rate limiter cleanup, and shuts
+// down every started engine
+func (s *Server) Close() {
+	s.jobs.CancelAll()
+	for _, limiter := range s.rateLimiters {
+		limiter.Close()
+	}
+
+	s.mu.Lock()
+	s.setWebhook(nil)
+	s.mu.Unlock()
+
+	if s.StockfishEngine != nil {
+		s.StockfishEngine.Close()
+	}
+	for _, engine := range s.engines {
+		if engine != s.StockfishEngine {
+			engine.Close()
+		}
+	}
+}
+
+// Health reports that the server is up, whether the active engine responds, and the middleware in use
+func (s *Server) Health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Don't hold the lock while pinging the engine, so health checks answer during long searches
+	s.mu.Lock()
+	engine := s.StockfishEngine
+	s.mu.Unlock()
+
+	json.NewEncoder(w).Encode(HealthResponse{
+		Status:        "ok",
+		EngineHealthy: engine != nil && engine.IsAlive(),
+		Middleware:    s.Middleware(),
+	})
+}
+
+// GetEngines lists the configured engines with their health status
+func (s *Server) GetEngines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := []EngineStatus{}
+	for _, spec := range s.engineSpecs {
+		engine := s.engines[spec.ID]
+		statuses = append(statuses, EngineStatus{
+			ID:      spec.ID,
+			Path:    spec.Path,
+			Active:  spec.ID == s.EngineConfig.EngineID,
+			Started: engine != nil,
+			Healthy: engine != nil && engine.IsAlive(),
+		})
+	}
+	json.NewEncoder(w).Encode(statuses)
+}
@@ -0,0 +1,123 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/uci"
+	"github.com/zully/chess-engine/internal/uci/ucitest"
+)
+
+func TestParseEngineSpecs(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []EngineSpec
+		wantErr bool
+	}{
+		{"stockfish=/usr/bin/stockfish", []EngineSpec{{"stockfish", "/usr/bin/stockfish"}}, false},
+		{" sf = /usr/bin/stockfish , lc0=/usr/bin/lc0 ,", []EngineSpec{{"sf", "/usr/bin/stockfish"}, {"lc0", "/usr/bin/lc0"}}, false},
+		{"sf=/opt/engines/a=b", []EngineSpec{{"sf", "/opt/engines/a=b"}}, false},
+		{"", nil, true},
+		{" , ", nil, true},
+		{"/usr/bin/stockfish", nil, true},
+		{"=/usr/bin/stockfish", nil, true},
+		{"sf=", nil, true},
+		{"sf=/a,sf=/b", nil, true},
+	}
+
+	for _, test := range tests {
+		specs, err := ParseEngineSpecs(test.value)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseEngineSpecs(%q) error = %v, want error %v", test.value, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(specs, test.want) {
+			t.Errorf("ParseEngineSpecs(%q) = %v, want %v", test.value, specs, test.want)
+		}
+	}
+}
+
+// getEngines fetches /api/engines, keyed by engine ID
+func getEngines(t *testing.T, s *Server) map[string]EngineStatus {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	s.GetEngines(recorder, httptest.NewRequest(http.MethodGet, "/api/engines", nil))
+	var statuses []EngineStatus
+	if err := json.NewDecoder(recorder.Body).Decode(&statuses); err != nil {
+		t.Fatalf("engines response %q: %v", recorder.Body.String(), err)
+	}
+	byID := make(map[string]EngineStatus)
+	for _, status := range statuses {
+		byID[status.ID] = status
+	}
+	return byID
+}
+
+// selectEngineID switches engines through POST /api/engine/config
+func selectEngineID(t *testing.T, s *Server, id string) int {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader(`{"engineId":"` + id + `"}`)
+	s.EngineConfigHandler(recorder, httptest.NewRequest(http.MethodPost, "/api/engine/config", body))
+	return recorder.Code
+}
+
+func TestSelectEngineStartsEnginesLazily(t *testing.T) {
+	alphaPath := ucitest.Path(t, ucitest.Config{Name: "alpha"})
+	betaPath := ucitest.Path(t, ucitest.Config{Name: "beta"})
+	alpha, err := uci.NewEngine(alphaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(board.NewBoard(), alpha)
+	defer s.Close()
+	s.SetEngines([]EngineSpec{{"alpha", alphaPath}, {"beta", betaPath}})
+
+	// Only the default engine runs until another one is selected
+	engines := getEngines(t, s)
+	if !engines["alpha"].Active || !engines["alpha"].Started || !engines["alpha"].Healthy {
+		t.Errorf("default engine status = %+v, want active, started and healthy", engines["alpha"])
+	}
+	if engines["beta"].Active || engines["beta"].Started || engines["beta"].Healthy {
+		t.Errorf("second engine status = %+v, want not started", engines["beta"])
+	}
+
+	if code := selectEngineID(t, s, "beta"); code != http.StatusOK {
+		t.Fatalf("selecting beta: status %d", code)
+	}
+	if s.StockfishEngine == alpha || s.StockfishEngine.Path() != betaPath {
+		t.Fatalf("active engine %s after selecting beta, want %s", s.StockfishEngine.Path(), betaPath)
+	}
+	if name, err := s.StockfishEngine.GetEngineInfo(); err != nil || name != "beta" {
+		t.Errorf("active engine reports %q (%v), want beta", name, err)
+	}
+	engines = getEngines(t, s)
+	if engines["alpha"].Active || !engines["alpha"].Healthy {
+		t.Errorf("default engine status after switching = %+v, want inactive but still running", engines["alpha"])
+	}
+	if !engines["beta"].Active || !engines["beta"].Started || !engines["beta"].Healthy {
+		t.Errorf("second engine status after switching = %+v, want active, started and healthy", engines["beta"])
+	}
+
+	// Switching back reuses the running instance
+	beta := s.StockfishEngine
+	if code := selectEngineID(t, s, "alpha"); code != http.StatusOK || s.StockfishEngine != alpha {
+		t.Errorf("selecting alpha again: status %d, same instance %v", code, s.StockfishEngine == alpha)
+	}
+	if code := selectEngineID(t, s, "beta"); code != http.StatusOK || s.StockfishEngine != beta {
+		t.Errorf("selecting beta again: status %d, same instance %v", code, s.StockfishEngine == beta)
+	}
+
+	if code := selectEngineID(t, s, "gamma"); code != http.StatusBadRequest {
+		t.Errorf("selecting an unknown engine: status %d, want %d", code, http.StatusBadRequest)
+	}
+	if s.EngineConfig.EngineID != "beta" || s.StockfishEngine != beta {
+		t.Errorf("unknown engine changed the selection to %q", s.EngineConfig.EngineID)
+	}
+}
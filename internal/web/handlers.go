@@ -1,57 +1,189 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/zully/chess-engine/internal/board"
 	"github.com/zully/chess-engine/internal/game"
+	"github.com/zully/chess-engine/internal/jobs"
 	"github.com/zully/chess-engine/internal/uci"
 )
 
+// Limits for the evaluation series along the top analysis line
+const (
+	pvSeriesDepth      = 8
+	pvSeriesMoveTimeMs = 100
+	pvSeriesBudget     = 1500 * time.Millisecond
+)
+
+// maxEvalCacheSize bounds the evaluation cache; it is cleared when full
+const maxEvalCacheSize = 4096
+
 // Server holds the dependencies for web handlers
 type Server struct {
+	// mu guards the game state below (board, clock, engine settings and the fields derived from
+	// them); handlers hold it for the whole request so moves and engine searches don't interleave.
+	// Handlers that change the game take it with lockGame, which rolls the game back on a panic.
+	mu sync.Mutex
+
 	GameBoard       *board.Board
 	StockfishEngine *uci.Engine
+	EngineConfig    game.EngineConfig
+	evalCache       map[string]int         // White-perspective evaluations keyed by FEN, guarded by mu
+	evalJob         string                 // Job filling evalCache for the history, guarded by mu
+	webhook         *webhook               // Notified after every move (nil if not registered), guarded by mu
+	jobs            *jobs.Manager          // Long-running background tasks such as full-game analysis
+	engineSpecs     []EngineSpec           // Engines the game can switch between
+	engines         map[string]*uci.Engine // Started engines by ID
+	engineThreads   int                    // Search threads for started engines (0 = engine default)
+	engineHashMB    int                    // Hash size for started engines (0 = engine default)
+
+	// engineMoves holds the settings of each engine move by half-move index, for /api/game/repro
+	engineMoves map[int]game.EngineConfig
+
+	// middleware is wrapped around the routes by Handler, the first outermost
+	middleware []func(http.Handler) http.Handler
+
+	// rateLimiters are the limiters created by SetupRoutes, stopped by Close
+	rateLimiters []*RateLimiter
+
+	// Clock times the game when configured with /api/clock/config (nil for untimed games)
+	Clock *game.GameClock
+
+	// AllowPrivateWebhooks lets webhooks target loopback, private and link-local addresses,
+	// e.g. a receiver on the same machine during development (off by default)
+	AllowPrivateWebhooks bool
+
+	// LegalityCheck logs positions where our legal moves differ from Stockfish's (diagnostic, off by default)
+	LegalityCheck bool
+
+	// TemplateDir is the directory holding index.html
+	TemplateDir string
+
+	// StaticDir is the directory served under /static/
+	StaticDir string
 }
 
 // NewServer creates a new web server instance
 func NewServer(gameBoard *board.Board, stockfishEngine *uci.Engine) *Server {
-	return &Server{
+	s := &Server{
 		GameBoard:       gameBoard,
 		StockfishEngine: stockfishEngine,
+		EngineConfig:    game.DefaultEngineConfig(),
+		evalCache:       make(map[string]int),
+		jobs:            jobs.NewManager(jobWorkers, jobTTL),
+		TemplateDir:     "web/templates/",
+		StaticDir:       "web/static/",
+	}
+	s.applyEngineStrength()
+	return s
+}
+
+// applyEngineStrength pushes the configured strength settings to the engine
+func (s *Server) applyEngineStrength() {
+	if s.StockfishEngine == nil {
+		return
+	}
+
+	if s.EngineConfig.UseElo {
+		if err := s.StockfishEngine.SetEloRating(s.EngineConfig.Elo); err != nil {
+			// ELO setting failed, engine will use current settings
+		}
+	} else {
+		if err := s.StockfishEngine.DisableStrengthLimit(); err != nil {
+			// Failed to disable strength limit, engine will use current settings
+		}
+	}
+
+	// Apply the playing style natively where the engine still supports contempt
+	if s.StockfishEngine.HasOption("Contempt") {
+		if err := s.StockfishEngine.SetContempt(game.StyleContempt(s.EngineConfig.Style)); err != nil {
+			// Contempt setting failed, engine will use its default
+		}
+	}
+}
+
+// searchEngineMove finds the engine's move, applying the playing style by choosing among
+// the top lines when the engine has no Contempt option of its own
+func (s *Server) searchEngineMove(fen string, depth, moveTimeMs int) (*uci.EngineMove, error) {
+	contempt := game.StyleContempt(s.EngineConfig.Style)
+	if contempt == 0 || s.StockfishEngine.HasOption("Contempt") {
+		return s.StockfishEngine.GetBestMoveWithLimits(fen, depth, moveTimeMs)
+	}
+
+	lines, err := s.StockfishEngine.GetMultiPVAnalysis(fen, depth, 3)
+	if err != nil {
+		return nil, err
+	}
+	line, ok := game.ChooseLineWithContempt(lines, contempt)
+	if !ok {
+		return nil, fmt.Errorf("no move received from engine")
+	}
+
+	return &uci.EngineMove{
+		From:  line.PV[0][0:2],
+		To:    line.PV[0][2:4],
+		Score: line.Score,
+		Depth: line.Depth,
+		UCI:   line.PV[0],
+		PV:    line.PV,
+	}, nil
+}
+
+// completeGameState creates the complete game state including the server's engine settings
+func (s *Server) completeGameState(events ...game.GameEvent) game.GameState {
+	state := game.CreateCompleteGameState(s.GameBoard, events, s.StockfishEngine)
+	state.ApplyClock(s.Clock)
+	engineConfig := s.EngineConfig
+	state.EngineConfig = &engineConfig
+
+	// Tell the registered webhook whenever a move was played
+	for _, event := range events {
+		if event.Type == game.EventMovePlayed {
+			s.notifyWebhook(state)
+			break
+		}
 	}
+	return state
+}
+
+// engineName names the active engine in messages: the name it reports, or its configured ID
+// The caller must hold s.mu
+func (s *Server) engineName() string {
+	if s.StockfishEngine != nil {
+		if name, err := s.StockfishEngine.GetEngineInfo(); err == nil {
+			return name
+		}
+	}
+	return s.EngineConfig.EngineID
 }
 
 func (s *Server) HomePage(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 	// Serve the HTML template file
-	http.ServeFile(w, r, "web/templates/index.html")
+	http.ServeFile(w, r, filepath.Join(s.TemplateDir, "index.html"))
 }
 
 func (s *Server) GetGameState(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	// Get current position evaluation from Stockfish if available
-	evaluation := 0
-	if s.StockfishEngine != nil {
-		currentFEN := s.GameBoard.ToFEN()
-		if eval, err := s.StockfishEngine.GetEvaluation(currentFEN); err == nil {
-			evaluation = eval
-		}
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	// Create complete game state
-	message := "Ready to play"
-	if s.GameBoard.WhiteToMove {
-		message = "White to move"
-	} else {
-		message = "Black to move"
+	state := s.completeGameState()
+	if r.URL.Query().Get("includeAttackMap") == "true" {
+		attackMap := game.NewAttackMap(s.GameBoard)
+		state.AttackMap = &attackMap
 	}
-
-	state := game.CreateCompleteGameState(s.GameBoard, message, evaluation, s.StockfishEngine)
 	json.NewEncoder(w).Encode(state)
 }
 
@@ -63,9 +195,9 @@ func (s *Server) MakeMove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		Move string `json:"move"` // Now expects UCI format (e.g., "e2e4", "a1e1")
-	}
+	defer s.lockGame()()
+
+	var req game.MoveRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
@@ -75,48 +207,40 @@ func (s *Server) MakeMove(w http.ResponseWriter, r *http.Request) {
 	// Validate UCI move format
 	uciMove := strings.TrimSpace(req.Move)
 	if !IsValidUCIMove(uciMove) {
-		state := game.CreateCompleteGameState(s.GameBoard, "", 0, s.StockfishEngine)
+		state := s.completeGameState()
 		state.Error = fmt.Sprintf("Invalid UCI move format: %s", uciMove)
 		json.NewEncoder(w).Encode(state)
 		return
 	}
 
+	if s.Clock != nil && s.Clock.CheckFlag() {
+		json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, "Time is up"))
+		return
+	}
+
+	// In diagnostic mode, keep the pre-move position to compare our legal moves with Stockfish's
+	var preMove *board.Board
+	if s.LegalityCheck {
+		preMove = s.GameBoard.Clone()
+	}
+
 	// Make the move on the board
 	if err := s.GameBoard.MakeUCIMove(uciMove); err != nil {
-		// Get current position evaluation from Stockfish if available
-		evaluation := 0
-		if s.StockfishEngine != nil {
-			currentFEN := s.GameBoard.ToFEN()
-			if eval, err := s.StockfishEngine.GetEvaluation(currentFEN); err == nil {
-				evaluation = eval
-			}
-		}
-
-		state := game.CreateCompleteGameState(s.GameBoard, "", evaluation, s.StockfishEngine)
+		state := s.completeGameState()
 		state.Error = fmt.Sprintf("Invalid move: %s", err.Error())
 		json.NewEncoder(w).Encode(state)
 		return
 	}
+	s.clockMoveMade()
 
-	// Get current position evaluation from Stockfish if available
-	evaluation := 0
-	if s.StockfishEngine != nil {
-		currentFEN := s.GameBoard.ToFEN()
-		if eval, err := s.StockfishEngine.GetEvaluation(currentFEN); err == nil {
-			evaluation = eval
-		}
-	}
-
-	// Determine the message
-	message := "Move made"
-	if s.GameBoard.WhiteToMove {
-		message = "White to move"
-	} else {
-		message = "Black to move"
+	// Only positions a move was played from are checked, once the response is written
+	if preMove != nil {
+		defer s.checkLegality(preMove)
 	}
 
 	// Create and return the complete game state
-	state := game.CreateCompleteGameState(s.GameBoard, message, evaluation, s.StockfishEngine)
+	san := s.GameBoard.MovesPlayed[len(s.GameBoard.MovesPlayed)-1]
+	state := s.completeGameState(game.MovePlayedEvent(san, uciMove, s.GameBoard.SideToMove().Opposite(), ""))
 	state.LastUCIMove = uciMove // Add the last UCI move to the response
 	json.NewEncoder(w).Encode(state)
 }
@@ -129,6 +253,9 @@ func (s *Server) GetEngineAnalysis(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// Check if Stockfish engine is available
 	if s.StockfishEngine == nil {
 		response := map[string]interface{}{
@@ -147,11 +274,25 @@ func (s *Server) GetEngineAnalysis(w http.ResponseWriter, r *http.Request) {
 		depth = req.Depth
 	}
 
-	// Get current position
-	currentFEN := s.GameBoard.ToFEN()
+	// Analyze the current position, or a client-supplied FEN once it has been
+	// validated and normalized (malformed FENs can crash the engine's search)
+	analysisBoard := s.GameBoard
+	if req.FEN != "" {
+		fenBoard, err := board.FromFEN(req.FEN)
+		if err == nil {
+			err = fenBoard.Validate()
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Invalid FEN: %v", err)})
+			return
+		}
+		analysisBoard = fenBoard
+	}
+	currentFEN := analysisBoard.ToFEN()
 
 	// Get multiple principal variations
-	multiPVLines, err := s.StockfishEngine.GetMultiPVAnalysis(currentFEN, depth, 3)
+	multiPVLines, err := s.StockfishEngine.GetMultiPVAnalysis(currentFEN, depth, s.EngineConfig.MultiPV)
 	if err != nil {
 		// Check if it's a communication failure and try to recover
 		if strings.Contains(err.Error(), "short write") ||
@@ -159,9 +300,10 @@ func (s *Server) GetEngineAnalysis(w http.ResponseWriter, r *http.Request) {
 			strings.Contains(err.Error(), "engine process") {
 
 			// Try to restart the engine
-			if restartErr := s.StockfishEngine.Restart("/usr/local/bin/stockfish"); restartErr == nil {
-				// Retry the analysis after restart
-				multiPVLines, err = s.StockfishEngine.GetMultiPVAnalysis(currentFEN, depth, 3)
+			if restartErr := s.StockfishEngine.Restart(s.StockfishEngine.Path()); restartErr == nil {
+				// Restore the configured strength and retry the analysis after restart
+				s.applyEngineStrength()
+				multiPVLines, err = s.StockfishEngine.GetMultiPVAnalysis(currentFEN, depth, s.EngineConfig.MultiPV)
 			}
 		}
 
@@ -187,39 +329,104 @@ func (s *Server) GetEngineAnalysis(w http.ResponseWriter, r *http.Request) {
 	analysisLines := make([]map[string]interface{}, len(multiPVLines))
 	for i, line := range multiPVLines {
 		// Convert UCI moves to algebraic notation
-		algebraicMoves := make([]string, len(line.PV))
-		for j, uciMove := range line.PV {
-			algebraicMoves[j] = ConvertUCIToAlgebraic(uciMove, s.GameBoard, j == 0)
+		algebraicMoves := ConvertPVToAlgebraic(line.PV, analysisBoard)
+
+		// Scores are reported from White's perspective, like pvEvalSeries; the engine's are
+		// for the side to move
+		score := line.Score
+		if !analysisBoard.WhiteToMove {
+			score = -score
 		}
 
 		// Get evaluation after first move if PV has moves
-		firstMoveEval := line.Score
+		firstMoveEval := score
 		if len(line.PV) > 0 {
-			if eval, err := GetEvaluationAfterMove(s.GameBoard, line.PV[0], s.StockfishEngine); err == nil {
+			if eval, err := GetEvaluationAfterMove(analysisBoard, line.PV[0], s.StockfishEngine); err == nil {
 				firstMoveEval = eval
 			}
 		}
 
 		analysisLines[i] = map[string]interface{}{
 			"lineNumber":    line.LineNumber,
-			"score":         line.Score,
+			"score":         score,
 			"depth":         line.Depth,
 			"pv":            line.PV,
 			"pvAlgebraic":   algebraicMoves,
 			"firstMoveEval": firstMoveEval,
 			"pvLength":      len(line.PV),
 		}
+
+		// Show how the evaluation evolves along the top line
+		if i == 0 {
+			analysisLines[i]["pvEvalSeries"] = s.pvEvalSeries(r.Context(), analysisBoard, line.PV)
+		}
 	}
 
 	response := map[string]interface{}{
 		"lines":   analysisLines,
 		"depth":   depth,
+		"fen":     currentFEN,
 		"message": fmt.Sprintf("Multi-PV analysis complete (depth %d, %d lines)", depth, len(multiPVLines)),
 	}
 
+	// Optionally report the opponent's best plan as a separate threat line
+	if req.Threat {
+		response["threat"] = s.getThreatLine(analysisBoard, depth)
+	}
+
 	json.NewEncoder(w).Encode(response)
 }
 
+// getThreatLine searches the null-moved position to find what the opponent is threatening
+func (s *Server) getThreatLine(gameBoard *board.Board, depth int) map[string]interface{} {
+	// A null move is illegal while in check - the check itself is the threat
+	if gameBoard.IsInCheck(gameBoard.SideToMove()) {
+		return map[string]interface{}{
+			"label":   "threat",
+			"inCheck": true,
+			"message": "King is in check - the check must be answered first",
+		}
+	}
+
+	// A short search is enough to find the opponent's immediate plan
+	threatDepth := 8
+	if depth < threatDepth {
+		threatDepth = depth
+	}
+
+	threatFEN := gameBoard.NullMoveFEN()
+	threatMove, err := s.StockfishEngine.GetBestMove(threatFEN, threatDepth)
+	if err != nil {
+		return map[string]interface{}{
+			"label": "threat",
+			"error": fmt.Sprintf("Threat search failed: %v", err),
+		}
+	}
+
+	// The threat line is played from the null-move position, with the opponent to move
+	algebraicMoves := threatMove.PV
+	if threatBoard, err := board.FromFEN(threatFEN); err == nil {
+		algebraicMoves = ConvertPVToAlgebraic(threatMove.PV, threatBoard)
+	}
+
+	// Like the analysis lines, the score is reported from White's perspective; the engine's
+	// is for the opponent, who moves first in the threat line
+	score := threatMove.Score
+	if gameBoard.WhiteToMove {
+		score = -score
+	}
+
+	return map[string]interface{}{
+		"label":       "threat",
+		"move":        threatMove.UCI,
+		"score":       score,
+		"depth":       threatDepth,
+		"pv":          threatMove.PV,
+		"pvAlgebraic": algebraicMoves,
+		"pvLength":    len(threatMove.PV),
+	}
+}
+
 func (s *Server) EngineMove(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -228,58 +435,40 @@ func (s *Server) EngineMove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req game.EngineRequest
-	json.NewDecoder(r.Body).Decode(&req)
-
-	state := game.GameState{Board: s.GameBoard}
+	defer s.lockGame()()
 
 	// Check if Stockfish engine is available
 	if s.StockfishEngine == nil {
-		state.Error = "Stockfish engine not available"
-		json.NewEncoder(w).Encode(state)
+		json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, "Stockfish engine not available"))
 		return
 	}
 
-	// Set depth (default to 6 if not specified)
-	depth := 6
-	if req.Depth > 0 && req.Depth <= 15 {
-		depth = req.Depth
+	if s.Clock != nil && s.Clock.CheckFlag() {
+		json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, "Time is up"))
+		return
 	}
 
-	// Set ELO/strength if specified
-	if req.Elo > 0 {
-		if req.Elo >= 1350 && req.Elo <= 2850 {
-			err := s.StockfishEngine.SetEloRating(req.Elo)
-			if err != nil {
-				// ELO setting failed, engine will use default strength
-			}
-		} else {
-			// Invalid ELO rating, use default strength
-			err := s.StockfishEngine.DisableStrengthLimit()
-			if err != nil {
-				// Failed to disable strength limit, engine will use current settings
-			}
-		}
-	} else {
-		// Full strength (disable ELO limiting)
-		err := s.StockfishEngine.DisableStrengthLimit()
-		if err != nil {
-			// Failed to disable strength limit, engine will use current settings
-		}
+	// Strength is configured persistently via /api/engine/config
+	depth := s.EngineConfig.Depth
+	moveTimeMs := s.EngineConfig.MoveTimeMs
+
+	// In diagnostic mode, keep the pre-move position to compare our legal moves with Stockfish's
+	var preMove *board.Board
+	if s.LegalityCheck {
+		preMove = s.GameBoard.Clone()
 	}
 
 	// Set current position in Stockfish using FEN
 	fen := s.GameBoard.ToFEN()
 	err := s.StockfishEngine.SetPosition(fen)
 	if err != nil {
-		state.Error = fmt.Sprintf("Failed to set position: %v", err)
-		json.NewEncoder(w).Encode(state)
+		json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, fmt.Sprintf("Failed to set position: %v", err)))
 		return
 	}
 
 	// Get the best move using Stockfish
 	currentFEN := s.GameBoard.ToFEN()
-	engineMove, err := s.StockfishEngine.GetBestMove(currentFEN, depth)
+	engineMove, err := s.searchEngineMove(currentFEN, depth, moveTimeMs)
 	if err != nil {
 		// Check if it's a communication failure and try to recover
 		if strings.Contains(err.Error(), "short write") ||
@@ -287,32 +476,37 @@ func (s *Server) EngineMove(w http.ResponseWriter, r *http.Request) {
 			strings.Contains(err.Error(), "engine process") {
 
 			// Try to restart the engine
-			if restartErr := s.StockfishEngine.Restart("/usr/local/bin/stockfish"); restartErr == nil {
-				// Retry the move after restart
-				engineMove, err = s.StockfishEngine.GetBestMove(currentFEN, depth)
+			if restartErr := s.StockfishEngine.Restart(s.StockfishEngine.Path()); restartErr == nil {
+				// Restore the configured strength and retry the move after restart
+				s.applyEngineStrength()
+				engineMove, err = s.searchEngineMove(currentFEN, depth, moveTimeMs)
 			}
 		}
 
 		if err != nil {
-			state.Error = fmt.Sprintf("Engine move failed: %v", err)
-			json.NewEncoder(w).Encode(state)
+			json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, fmt.Sprintf("Engine move failed: %v", err)))
 			return
 		}
 	}
 
 	if engineMove == nil {
-		state.Error = "No move received from engine"
-		json.NewEncoder(w).Encode(state)
+		json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, "No move received from engine"))
 		return
 	}
 
 	// Execute the move using UCI notation directly
 	err = s.GameBoard.MakeUCIMove(engineMove.UCI)
 	if err != nil {
-		state.Error = fmt.Sprintf("Failed to execute engine move %s: %v", engineMove.UCI, err)
-		json.NewEncoder(w).Encode(state)
+		json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, fmt.Sprintf("Failed to execute engine move %s: %v", engineMove.UCI, err)))
 		return
 	}
+	s.recordEngineMove()
+	s.clockMoveMade()
+
+	// Only positions a move was played from are checked, once the response is written
+	if preMove != nil {
+		defer s.checkLegality(preMove)
+	}
 
 	// Get the algebraic notation from the move history (last move added)
 	var moveNotation string
@@ -322,66 +516,77 @@ func (s *Server) EngineMove(w http.ResponseWriter, r *http.Request) {
 		moveNotation = engineMove.UCI // Fallback to UCI if no algebraic notation available
 	}
 
-	// Update game state
-	state.InCheck = s.GameBoard.IsInCheck(s.GameBoard.WhiteToMove)
-	state.IsCheckmate = s.GameBoard.IsCheckmate(s.GameBoard.WhiteToMove)
-	state.GameOver = state.IsCheckmate
+	// Create complete game state with evaluation of the position after the move
+	state := s.completeGameState(
+		game.MovePlayedEvent(moveNotation, engineMove.UCI, s.GameBoard.SideToMove().Opposite(), s.engineName()),
+		game.EngineInfoEvent(engineMove.Depth, engineMove.Score, engineMove.PV),
+	)
 
-	// Check for draws
-	isDraw := s.GameBoard.IsDraw()
-	drawReason := ""
-	if isDraw {
-		if s.GameBoard.IsThreefoldRepetition() {
-			drawReason = "Threefold repetition"
-		} else {
-			drawReason = "Stalemate"
-		}
+	// Add the UCI move for last move highlighting
+	state.LastUCIMove = engineMove.UCI
+
+	json.NewEncoder(w).Encode(state)
+}
+
+func (s *Server) UndoMove(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
-	state.Draw = isDraw
-	state.DrawReason = drawReason
-	state.GameOver = state.IsCheckmate || isDraw
 
-	// Set message with engine evaluation and PV info
-	pvInfo := ""
-	if len(engineMove.PV) > 1 {
-		pvLen := len(engineMove.PV)
-		if pvLen > 3 {
-			pvLen = 3
-		}
-		pvInfo = fmt.Sprintf(", PV: %s", strings.Join(engineMove.PV[:pvLen], " "))
-		if len(engineMove.PV) > 3 {
-			pvInfo += "..."
+	defer s.lockGame()()
+
+	// Number of half-moves to undo: /api/undo undoes one, /api/undo/{n} undoes n
+	count := 1
+	if countStr := strings.TrimPrefix(r.URL.Path, "/api/undo/"); countStr != r.URL.Path && countStr != "" {
+		n, err := strconv.Atoi(countStr)
+		if err != nil || n < 1 {
+			http.Error(w, "Undo count must be a positive integer", http.StatusBadRequest)
+			return
 		}
+		count = n
 	}
-	baseMessage := fmt.Sprintf("Stockfish played %s (depth: %d, score: %d%s)",
-		moveNotation, engineMove.Depth, engineMove.Score, pvInfo)
 
-	if isDraw {
-		baseMessage += fmt.Sprintf(" - Draw! %s", drawReason)
-	} else if state.IsCheckmate {
-		if s.GameBoard.WhiteToMove {
-			baseMessage += " - Black wins!"
-		} else {
-			baseMessage += " - White wins!"
-		}
-	} else if state.InCheck {
-		if s.GameBoard.WhiteToMove {
-			baseMessage += " - White in check!"
-		} else {
-			baseMessage += " - Black in check!"
-		}
+	// Check if there are moves to undo
+	if len(s.GameBoard.MovesPlayed) == 0 {
+		json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, "No moves to undo!"))
+		return
 	}
 
-	// Create complete game state with evaluation
-	state = game.CreateCompleteGameState(s.GameBoard, baseMessage, engineMove.Evaluation, s.StockfishEngine)
+	// Undoing more moves than were played goes back to the initial position
+	if count > len(s.GameBoard.MovesPlayed) {
+		count = len(s.GameBoard.MovesPlayed)
+	}
 
-	// Add the UCI move for last move highlighting
-	state.LastUCIMove = engineMove.UCI
+	// Keep the current game, and the notation of the moves being taken back
+	previous := s.GameBoard.Clone()
+	kept := len(previous.UCIMoves) - count
+	undoneNotations := previous.MovesPlayed[kept:]
+
+	// Start again from the initial position and replay all moves except the undone ones
+	s.GameBoard.Reset()
+	if _, err := s.GameBoard.ApplyMoves(previous.UCIMoves[:kept]); err != nil {
+		// If replay fails, restore the original board state
+		// This shouldn't happen, but just in case
+		*s.GameBoard = *previous
+		json.NewEncoder(w).Encode(game.ErrorState(s.GameBoard, fmt.Sprintf("Failed to undo move: %v", err)))
+		return
+	}
+
+	s.trimEngineMoves()
+	s.clockFollowSideToMove()
+
+	// Create and return the updated game state (including the evaluation)
+	state := s.completeGameState(game.UndoEvent(undoneNotations))
+	state.UndoneCount = count
+	state.UndoneNotations = undoneNotations
 
 	json.NewEncoder(w).Encode(state)
 }
 
-func (s *Server) UndoMove(w http.ResponseWriter, r *http.Request) {
+func (s *Server) ResetGame(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	if r.Method != http.MethodPost {
@@ -389,101 +594,239 @@ func (s *Server) UndoMove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if there are moves to undo
-	if len(s.GameBoard.MovesPlayed) == 0 {
-		state := game.GameState{
-			Board: s.GameBoard,
-			Error: "No moves to undo!",
-		}
-		json.NewEncoder(w).Encode(state)
+	defer s.lockGame()()
+
+	// Start a new game on the same board, untimed until the clock is configured again
+	s.GameBoard.Reset()
+	s.engineMoves = nil
+	s.evalCache = make(map[string]int)
+	s.Clock = nil
+
+	// Create complete game state with evaluation
+	state := s.completeGameState(game.ResetEvent())
+	state.LastUCIMove = "" // Clear last move on reset
+	json.NewEncoder(w).Encode(state)
+}
+
+func (s *Server) GetFEN(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Store the current moves list
-	currentMoves := make([]string, len(s.GameBoard.MovesPlayed))
-	copy(currentMoves, s.GameBoard.MovesPlayed)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Remove the last move
-	movesToReplay := currentMoves[:len(currentMoves)-1]
+	sideToMove := "white"
+	if !s.GameBoard.WhiteToMove {
+		sideToMove = "black"
+	}
 
-	// Create a fresh board
-	s.GameBoard = board.NewBoard()
+	uciMoves := make([]string, len(s.GameBoard.UCIMoves))
+	copy(uciMoves, s.GameBoard.UCIMoves)
 
-	// Replay all moves except the last one
-	for _, move := range movesToReplay {
-		err := s.GameBoard.MakeMove(move)
-		if err != nil {
-			// If replay fails, restore the original board state
-			// This shouldn't happen, but just in case
-			s.GameBoard = board.NewBoard()
-			for _, originalMove := range currentMoves {
-				s.GameBoard.MakeMove(originalMove)
-			}
-			state := game.GameState{
-				Board: s.GameBoard,
-				Error: fmt.Sprintf("Failed to undo move: %v", err),
-			}
-			json.NewEncoder(w).Encode(state)
+	json.NewEncoder(w).Encode(FENResponse{
+		FEN:        s.GameBoard.ToFEN(),
+		MoveNumber: s.GameBoard.FullMoveNumber,
+		SideToMove: sideToMove,
+		UCIMoves:   uciMoves,
+	})
+}
+
+// GetAttackMap lists the squares attacked by each side in the current position
+func (s *Server) GetAttackMap(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	json.NewEncoder(w).Encode(game.NewAttackMap(s.GameBoard))
+}
+
+func (s *Server) GetHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Optional pagination by move number (inclusive)
+	from, to := 0, 0
+	if value := r.URL.Query().Get("from"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid 'from' parameter", http.StatusBadRequest)
+			return
+		}
+		from = n
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid 'to' parameter", http.StatusBadRequest)
 			return
 		}
+		to = n
 	}
 
-	// Create and return the updated game state
-	inCheck := s.GameBoard.IsInCheck(s.GameBoard.WhiteToMove)
-	isCheckmate := false
-	if inCheck {
-		isCheckmate = s.GameBoard.IsCheckmate(s.GameBoard.WhiteToMove)
+	// Only evaluations computed so far are used: the missing ones are computed by a background
+	// job, so no engine search holds up the request or the server lock
+	var missing []string
+	var evaluate game.PositionEvaluator
+	if s.StockfishEngine != nil {
+		evaluate = func(b *board.Board) (int, bool) {
+			fen := b.ToFEN()
+			eval, exists := s.evalCache[fen]
+			if !exists {
+				missing = append(missing, fen)
+			}
+			return eval, exists
+		}
 	}
 
-	isDraw := s.GameBoard.IsDraw()
-	drawReason := ""
-	if isDraw {
-		if s.GameBoard.IsThreefoldRepetition() {
-			drawReason = "Threefold repetition"
-		} else {
-			drawReason = "Stalemate"
+	entries, err := game.BuildHistory(s.GameBoard, evaluate)
+	if err != nil {
+		response := map[string]interface{}{
+			"error": fmt.Sprintf("Failed to build history: %v", err),
 		}
+		json.NewEncoder(w).Encode(response)
+		return
 	}
 
-	state := game.GameState{
-		Board:         s.GameBoard,
-		InCheck:       inCheck,
-		IsCheckmate:   isCheckmate,
-		GameOver:      isCheckmate || isDraw,
-		Draw:          isDraw,
-		DrawReason:    drawReason,
-		ThreefoldRep:  s.GameBoard.IsThreefoldRepetition(),
-		PositionCount: s.GameBoard.GetPositionCount(),
+	moves := []game.HistoryEntry{}
+	for _, entry := range entries {
+		if (from > 0 && entry.MoveNumber < from) || (to > 0 && entry.MoveNumber > to) {
+			continue
+		}
+		moves = append(moves, entry)
 	}
 
-	lastMove := currentMoves[len(currentMoves)-1]
-	state.Message = fmt.Sprintf("Undid move %s", lastMove)
+	response := HistoryResponse{Moves: moves}
+	if len(missing) > 0 {
+		response.Pending = true
+		response.JobID = s.evaluateInBackground(missing)
+	}
+	json.NewEncoder(w).Encode(response)
+}
 
-	json.NewEncoder(w).Encode(state)
+// pvEvalSeries evaluates the positions along a PV with quick searches, within a time budget
+func (s *Server) pvEvalSeries(ctx context.Context, start *board.Board, pv []string) []int {
+	ctx, cancel := context.WithTimeout(ctx, pvSeriesBudget)
+	defer cancel()
+
+	evaluate := func(b *board.Board) (int, bool) {
+		eval, err := s.StockfishEngine.GetEvaluationWithLimits(b.ToFEN(), pvSeriesDepth, pvSeriesMoveTimeMs)
+		if err != nil {
+			return 0, false
+		}
+		if !b.WhiteToMove {
+			eval = -eval // The engine reports scores for the side to move
+		}
+		return eval, true
+	}
+	return game.PVEvalSeries(ctx, start, pv, evaluate)
 }
 
-func (s *Server) ResetGame(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+// evaluateInBackground starts a job evaluating positions into the evaluation cache, unless one
+// is already running, and returns the job's ID. Like AnalyzeHistory the job runs on its own
+// engine process. The caller must hold s.mu
+func (s *Server) evaluateInBackground(fens []string) string {
+	if job, exists := s.jobs.Get(s.evalJob); exists && (job.Status == jobs.StatusQueued || job.Status == jobs.StatusRunning) {
+		return s.evalJob
+	}
 
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+	enginePath := s.StockfishEngine.Path()
+	s.evalJob = s.jobs.Submit(func(ctx context.Context, report jobs.Reporter) (interface{}, error) {
+		return nil, s.evaluatePositions(ctx, fens, enginePath, report)
+	})
+	return s.evalJob
+}
+
+// evaluatePositions evaluates positions with a dedicated engine, caching each evaluation as
+// soon as it is known so the history fills in while the job runs
+func (s *Server) evaluatePositions(ctx context.Context, fens []string, enginePath string, report jobs.Reporter) error {
+	report(0, "Starting engine")
+	engine, err := uci.NewEngine(enginePath)
+	if err != nil {
+		return fmt.Errorf("failed to start evaluation engine: %v", err)
 	}
+	defer engine.Close()
 
-	// Create a new board
-	s.GameBoard = board.NewBoard()
+	for i, fen := range fens {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-	// Get initial evaluation
-	evaluation := 0
-	if s.StockfishEngine != nil {
-		currentFEN := s.GameBoard.ToFEN()
-		if eval, err := s.StockfishEngine.GetEvaluation(currentFEN); err == nil {
-			evaluation = eval
+		b, err := board.FromFEN(fen)
+		if err != nil {
+			return err
+		}
+		if eval, _, ok := game.EvaluatePosition(b, engine); ok {
+			s.mu.Lock()
+			// Evaluations of a different engine would be mixed in after a switch
+			if s.StockfishEngine != nil && s.StockfishEngine.Path() == enginePath {
+				s.cacheEvaluation(fen, eval)
+			}
+			s.mu.Unlock()
 		}
+		report((i+1)*100/len(fens), fmt.Sprintf("Evaluated %d of %d positions", i+1, len(fens)))
 	}
+	return nil
+}
 
-	// Create complete game state with evaluation
-	state := game.CreateCompleteGameState(s.GameBoard, "Game reset. White to move.", evaluation, s.StockfishEngine)
-	state.LastUCIMove = "" // Clear last move on reset
-	json.NewEncoder(w).Encode(state)
+// cacheEvaluation stores a White-perspective evaluation; the caller must hold s.mu
+func (s *Server) cacheEvaluation(fen string, eval int) {
+	if len(s.evalCache) >= maxEvalCacheSize {
+		s.evalCache = make(map[string]int)
+	}
+	s.evalCache[fen] = eval
+}
+
+func (s *Server) EngineConfigHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(s.EngineConfig)
+	case http.MethodPost:
+		config := s.EngineConfig
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if err := config.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		if config.EngineID != s.EngineConfig.EngineID {
+			if err := s.selectEngine(config.EngineID); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+				return
+			}
+		}
+
+		s.EngineConfig = config
+		s.applyEngineStrength()
+		json.NewEncoder(w).Encode(s.EngineConfig)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
 }
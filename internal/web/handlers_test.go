@@ -0,0 +1,373 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
+	"github.com/zully/chess-engine/internal/jobs"
+	"github.com/zully/chess-engine/internal/uci"
+	"github.com/zully/chess-engine/internal/uci/ucitest"
+)
+
+// startStockfish starts the engine from CHESS_ENGINE_PATH or the PATH, skipping the test without one
+func startStockfish(t *testing.T) *uci.Engine {
+	t.Helper()
+	path := os.Getenv("CHESS_ENGINE_PATH")
+	if path == "" {
+		var err error
+		if path, err = exec.LookPath("stockfish"); err != nil {
+			t.Skip("Stockfish not found; set CHESS_ENGINE_PATH to run")
+		}
+	}
+	engine, err := uci.NewEngine(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return engine
+}
+
+// startFakeEngine starts a scripted engine, closed when the test ends
+func startFakeEngine(t *testing.T, config ucitest.Config) *uci.Engine {
+	t.Helper()
+	engine, err := uci.NewEngine(ucitest.Path(t, config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { engine.Close() })
+	return engine
+}
+
+// waitForJob waits until a background job has finished
+func waitForJob(t *testing.T, s *Server, id string) jobs.Job {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		job, exists := s.jobs.Get(id)
+		if !exists {
+			t.Fatalf("job %s not found", id)
+		}
+		if job.Status != jobs.StatusQueued && job.Status != jobs.StatusRunning {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return jobs.Job{}
+}
+
+// getHistory fetches /api/history
+func getHistory(t *testing.T, s *Server) HistoryResponse {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	s.GetHistory(recorder, httptest.NewRequest(http.MethodGet, "/api/history", nil))
+	var response HistoryResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("history response %q: %v", recorder.Body.String(), err)
+	}
+	return response
+}
+
+func TestHistoryEvaluatesInBackground(t *testing.T) {
+	s := NewServer(board.NewBoard(), startFakeEngine(t, ucitest.Config{}))
+	defer s.Close()
+	if _, err := s.GameBoard.ApplyMoves([]string{"e2e4", "d7d5", "e4d5"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first request answers right away, without evaluations
+	first := getHistory(t, s)
+	if !first.Pending || first.JobID == "" {
+		t.Fatalf("first history response = %+v, want pending with a job", first)
+	}
+	if len(first.Moves) != 2 || first.Moves[0].White.Eval != nil {
+		t.Fatalf("first history response moves = %+v, want 2 moves without evaluations", first.Moves)
+	}
+	if again := getHistory(t, s); again.JobID != first.JobID {
+		t.Errorf("second request started job %s while %s was running", again.JobID, first.JobID)
+	}
+
+	if job := waitForJob(t, s, first.JobID); job.Status != jobs.StatusDone {
+		t.Fatalf("evaluation job = %+v", job)
+	}
+
+	// Once the job is done every half-move has its evaluation
+	done := getHistory(t, s)
+	if done.Pending || done.JobID != "" {
+		t.Errorf("history after the job = %+v, want nothing pending", done)
+	}
+	for _, entry := range done.Moves {
+		for _, move := range []*game.HistoryMove{entry.White, entry.Black} {
+			if move != nil && move.Eval == nil {
+				t.Errorf("move %s has no evaluation after the job", move.UCI)
+			}
+		}
+	}
+}
+
+// evaluationOf calls a game handler and returns the evaluation fields of its state
+func evaluationOf(t *testing.T, handler http.HandlerFunc, method, path, body string) (eval, depth int) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	var state struct {
+		Evaluation      int  `json:"evaluation"`
+		EvaluationDepth int  `json:"evaluationDepth"`
+		HasEvaluation   bool `json:"hasEvaluation"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&state); err != nil || !state.HasEvaluation {
+		t.Fatalf("%s %s: state %q has no evaluation (%v)", method, path, recorder.Body.String(), err)
+	}
+	return state.Evaluation, state.EvaluationDepth
+}
+
+func TestEvaluationAgreesAcrossEndpoints(t *testing.T) {
+	s := NewServer(board.NewBoard(), startFakeEngine(t, ucitest.Config{}))
+	defer s.Close()
+
+	move := func(uciMove string) (int, int) {
+		return evaluationOf(t, s.MakeMove, http.MethodPost, "/api/move", `{"move":"`+uciMove+`"}`)
+	}
+	state := func() (int, int) {
+		return evaluationOf(t, s.GetGameState, http.MethodGet, "/api/state", "")
+	}
+
+	start, startDepth := state()
+	if start != ucitest.StaticEvalBonus || startDepth != 0 {
+		t.Errorf("start position evaluation = %d at depth %d, want the static %d", start, startDepth, ucitest.StaticEvalBonus)
+	}
+
+	afterE4, _ := move("e2e4")
+	if eval, _ := state(); eval != afterE4 {
+		t.Errorf("state after 1.e4 = %d, move response = %d", eval, afterE4)
+	}
+	afterF5, _ := move("f7f5")
+
+	// The check can't be evaluated statically and falls back to a search
+	afterQh5, qh5Depth := move("d1h5")
+	if qh5Depth != uci.EvaluationDepth {
+		t.Errorf("evaluation depth in check = %d, want %d", qh5Depth, uci.EvaluationDepth)
+	}
+	if eval, depth := state(); eval != afterQh5 || depth != qh5Depth {
+		t.Errorf("state after 2.Qh5+ = %d at depth %d, move response = %d at depth %d", eval, depth, afterQh5, qh5Depth)
+	}
+
+	// The history job evaluates the same positions the same way
+	history := getHistory(t, s)
+	if history.JobID != "" {
+		waitForJob(t, s, history.JobID)
+		history = getHistory(t, s)
+	}
+	want := []int{afterE4, afterF5, afterQh5}
+	var got []int
+	for _, entry := range history.Moves {
+		for _, halfMove := range []*game.HistoryMove{entry.White, entry.Black} {
+			if halfMove != nil && halfMove.Eval != nil {
+				got = append(got, *halfMove.Eval)
+			}
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("history evaluations = %v, want %v", got, want)
+	}
+
+	if eval, depth := evaluationOf(t, s.UndoMove, http.MethodPost, "/api/undo", ""); eval != afterF5 || depth != 0 {
+		t.Errorf("undo evaluation = %d at depth %d, want %d at depth 0", eval, depth, afterF5)
+	}
+	if eval, depth := evaluationOf(t, s.ResetGame, http.MethodPost, "/api/reset", ""); eval != start || depth != startDepth {
+		t.Errorf("reset evaluation = %d at depth %d, want %d at depth %d", eval, depth, start, startDepth)
+	}
+}
+
+func TestEngineMoveGivingCheckEvents(t *testing.T) {
+	// Winning the queen with Rxe7+ is the fake engine's best move, and it gives check
+	b, err := board.FromFEN("4k3/4q3/8/8/8/8/8/4R1K1 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(b, startFakeEngine(t, ucitest.Config{Name: "fakefish"}))
+	defer s.Close()
+
+	recorder := httptest.NewRecorder()
+	s.EngineMove(recorder, httptest.NewRequest(http.MethodPost, "/api/engine/move", nil))
+	var state struct {
+		Message string           `json:"message"`
+		Events  []game.GameEvent `json:"events"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&state); err != nil {
+		t.Fatalf("engine move response %q: %v", recorder.Body.String(), err)
+	}
+
+	var types []game.GameEventType
+	for _, event := range state.Events {
+		types = append(types, event.Type)
+	}
+	want := []game.GameEventType{game.EventMovePlayed, game.EventEngineInfo, game.EventCheck}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+
+	played := state.Events[0].Data
+	if played["san"] != "Rxe7+" || played["uci"] != "e1e7" || played["side"] != "white" ||
+		played["engine"] != true || played["engineName"] != "fakefish" {
+		t.Errorf("move-played data = %v", played)
+	}
+	if info := state.Events[1].Data; info["depth"] == nil || info["score"] == nil {
+		t.Errorf("engine-info data = %v", info)
+	}
+	if check := state.Events[2].Data; check["side"] != "black" {
+		t.Errorf("check data = %v", check)
+	}
+
+	if !strings.HasPrefix(state.Message, "fakefish played Rxe7+ (depth: ") || !strings.HasSuffix(state.Message, " - Black in check!") {
+		t.Errorf("message = %q", state.Message)
+	}
+}
+
+// analysisLine is the part of an /api/analysis line the tests look at
+type analysisLine struct {
+	Score         int      `json:"score"`
+	FirstMoveEval int      `json:"firstMoveEval"`
+	PV            []string `json:"pv"`
+	PVEvalSeries  []int    `json:"pvEvalSeries"`
+}
+
+// analyze posts an analysis request and returns its lines
+func analyze(t *testing.T, s *Server, body string) []analysisLine {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	s.GetEngineAnalysis(recorder, httptest.NewRequest(http.MethodPost, "/api/analysis", strings.NewReader(body)))
+	var response struct {
+		Lines []analysisLine `json:"lines"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil || len(response.Lines) == 0 {
+		t.Fatalf("analysis response %q: %v", recorder.Body.String(), err)
+	}
+	return response.Lines
+}
+
+func TestAnalysisPVEvalSeries(t *testing.T) {
+	tests := []struct {
+		pvLength int
+		want     int
+	}{
+		{3, 3},
+		{game.PVSeriesPlies + 2, game.PVSeriesPlies},
+	}
+
+	for _, test := range tests {
+		s := NewServer(board.NewBoard(), startFakeEngine(t, ucitest.Config{PVLength: test.pvLength}))
+		if _, err := s.GameBoard.ApplyMoves([]string{"e2e4", "e7e5"}); err != nil {
+			t.Fatal(err)
+		}
+		before := s.GameBoard.Clone()
+
+		top := analyze(t, s, `{"depth": 5}`)[0]
+		if len(top.PV) != test.pvLength || len(top.PVEvalSeries) != test.want {
+			t.Errorf("PV of %d moves: series of %d evaluations, want %d", len(top.PV), len(top.PVEvalSeries), test.want)
+		}
+
+		// Playing the line out for the series leaves the game alone
+		if !reflect.DeepEqual(s.GameBoard, before) {
+			t.Errorf("analysis changed the game: %s, was %s", s.GameBoard.ToFEN(), before.ToFEN())
+		}
+		s.Close()
+	}
+}
+
+func TestAnalysisScoresFromWhitesPerspective(t *testing.T) {
+	s := NewServer(board.NewBoard(), startFakeEngine(t, ucitest.Config{}))
+	defer s.Close()
+
+	// Black is a queen up and to move: the engine's score is positive, White's is not
+	top := analyze(t, s, `{"fen": "3qk3/8/8/8/8/8/8/4K3 b - - 0 1", "depth": 5}`)[0]
+	if top.Score > -800 {
+		t.Errorf("score = %d, want Black's advantage as a negative score", top.Score)
+	}
+	if top.FirstMoveEval > -800 {
+		t.Errorf("firstMoveEval = %d, want Black's advantage as a negative score", top.FirstMoveEval)
+	}
+	for i, eval := range top.PVEvalSeries {
+		if eval > -800 {
+			t.Errorf("pvEvalSeries[%d] = %d, want Black's advantage as a negative score", i, eval)
+		}
+	}
+	if len(s.GameBoard.UCIMoves) != 0 {
+		t.Errorf("analysis of a FEN changed the game: %v", s.GameBoard.UCIMoves)
+	}
+}
+
+func TestThreatLineInCheck(t *testing.T) {
+	// After 1.e4 f5 2.Qh5+ the check itself is the threat; no search is needed
+	b, err := board.FromFEN("rnbqkbnr/ppppp1pp/8/5p1Q/4P3/8/PPPP1PPP/RNB1KBNR b KQkq - 1 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(board.NewBoard(), nil)
+	defer s.Close()
+
+	threat := s.getThreatLine(b, 10)
+	if threat["inCheck"] != true || threat["label"] != "threat" {
+		t.Errorf("threat line in check = %v", threat)
+	}
+	if _, searched := threat["move"]; searched {
+		t.Errorf("threat line in check has a move: %v", threat)
+	}
+}
+
+func TestThreatLineScoreFromWhitesPerspective(t *testing.T) {
+	s := NewServer(board.NewBoard(), startFakeEngine(t, ucitest.Config{}))
+	defer s.Close()
+
+	tests := []struct {
+		fen       string
+		wantWhite bool
+	}{
+		// The opponent of the side to move is a queen up, which the score shows from White's side
+		{"3qk3/8/8/8/8/8/8/4K3 w - - 0 1", false},
+		{"4k3/8/8/8/8/8/8/3QK3 b - - 0 1", true},
+	}
+	for _, test := range tests {
+		b, err := board.FromFEN(test.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		threat := s.getThreatLine(b, 5)
+		score, ok := threat["score"].(int)
+		if !ok {
+			t.Fatalf("%s: threat line has no score: %v", test.fen, threat)
+		}
+		if test.wantWhite && score < 800 || !test.wantWhite && score > -800 {
+			t.Errorf("%s: threat score = %d, want the queen's side ahead from White's perspective", test.fen, score)
+		}
+	}
+}
+
+func TestThreatLineFindsMateThreat(t *testing.T) {
+	engine := startStockfish(t)
+	s := NewServer(board.NewBoard(), engine)
+	defer s.Close()
+
+	// Black to move after 3.Qh5: White threatens Qxf7#
+	b, err := board.FromFEN("r1bqkbnr/pppp1ppp/2n5/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR b KQkq - 3 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	threat := s.getThreatLine(b, 10)
+	if threat["move"] != "h5f7" {
+		t.Errorf("threat move = %v, want h5f7 (threat line %v)", threat["move"], threat)
+	}
+	if algebraic, ok := threat["pvAlgebraic"].([]string); !ok || len(algebraic) == 0 || algebraic[0] != "Qxf7#" {
+		t.Errorf("threat line in algebraic = %v, want it to start with Qxf7#", threat["pvAlgebraic"])
+	}
+}
@@ -0,0 +1,201 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
+	"github.com/zully/chess-engine/internal/jobs"
+	"github.com/zully/chess-engine/internal/uci"
+)
+
+// Background job settings
+const (
+	jobWorkers = 2         // Jobs running at once; each analysis job starts its own Stockfish process
+	jobTTL     = time.Hour // How long finished jobs (and their results) can still be fetched
+
+	annotateDefaultDepth = 12 // Search depth per position for game reviews
+	annotateMaxDepth     = 20
+)
+
+// AnalyzeHistory starts a background evaluation of every move in the game
+func (s *Server) AnalyzeHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.StockfishEngine == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Stockfish engine not available"})
+		return
+	}
+
+	// The job works on a snapshot so later moves don't affect it, and on its own
+	// engine process so it never interleaves with the engine used by requests
+	snapshot := s.GameBoard.Clone()
+	enginePath := s.StockfishEngine.Path()
+
+	id := s.jobs.Submit(func(ctx context.Context, report jobs.Reporter) (interface{}, error) {
+		return analyzeHistory(ctx, snapshot, enginePath, report)
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(JobSubmitted{ID: id})
+}
+
+// analyzeHistory builds the evaluated move history using a dedicated engine
+func analyzeHistory(ctx context.Context, gameBoard *board.Board, enginePath string, report jobs.Reporter) (interface{}, error) {
+	report(0, "Starting engine")
+	engine, err := uci.NewEngine(enginePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start analysis engine: %v", err)
+	}
+	defer engine.Close()
+
+	evaluate := func(b *board.Board) (int, bool) {
+		eval, _, ok := game.EvaluatePosition(b, engine)
+		return eval, ok
+	}
+	progress := func(done, total int) {
+		report(done*100/total, fmt.Sprintf("Analyzed %d of %d moves", done, total))
+	}
+
+	entries, err := game.BuildHistoryContext(ctx, gameBoard, evaluate, progress)
+	if err != nil {
+		return nil, err
+	}
+	return HistoryResponse{Moves: entries}, nil
+}
+
+// AnnotateGame starts a background review of a game, by default the current one
+func (s *Server) AnnotateGame(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.StockfishEngine == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Stockfish engine not available"})
+		return
+	}
+
+	var req AnnotateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	depth := annotateDefaultDepth
+	if req.Depth < 0 || req.Depth > annotateMaxDepth {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Depth must be between 1 and %d", annotateMaxDepth)})
+		return
+	} else if req.Depth > 0 {
+		depth = req.Depth
+	}
+
+	moves := append([]string(nil), s.GameBoard.UCIMoves...)
+	if req.Moves != nil {
+		moves = req.Moves
+		if _, err := board.NewBoard().ApplyMoves(moves); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Invalid %v", err)})
+			return
+		}
+	}
+
+	// Like AnalyzeHistory, the review runs on its own engine process
+	enginePath := s.StockfishEngine.Path()
+
+	id := s.jobs.Submit(func(ctx context.Context, report jobs.Reporter) (interface{}, error) {
+		return annotateGame(ctx, moves, enginePath, depth, report)
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(JobSubmitted{ID: id})
+}
+
+// annotateGame reviews a game using a dedicated engine
+func annotateGame(ctx context.Context, moves []string, enginePath string, depth int, report jobs.Reporter) (interface{}, error) {
+	report(0, "Starting engine")
+	engine, err := uci.NewEngine(enginePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start analysis engine: %v", err)
+	}
+	defer engine.Close()
+
+	progress := func(done, total int) {
+		report(done*100/total, fmt.Sprintf("Reviewed %d of %d moves", done, total))
+	}
+
+	annotated, err := game.AnnotateGameContext(ctx, moves, engine, depth, progress)
+	if err != nil {
+		return nil, err
+	}
+	return AnnotateResponse{Moves: annotated}, nil
+}
+
+// AnnotationJob reports the game review job at /api/game/annotate/{id}
+func (s *Server) AnnotationJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.writeJob(w, strings.TrimPrefix(r.URL.Path, "/api/game/annotate/"))
+}
+
+// Job reports (GET) or cancels (DELETE) the background job at /api/jobs/{id}
+func (s *Server) Job(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/jobs/")
+	if id == "" || strings.Contains(id, "/") {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Job not found"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodDelete:
+		s.jobs.Cancel(id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.writeJob(w, id)
+}
+
+// writeJob writes the job with the given ID, or a 404 if there is none
+func (s *Server) writeJob(w http.ResponseWriter, id string) {
+	job, exists := s.jobs.Get(id)
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Job not found"})
+		return
+	}
+	json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,13 @@
+package web
+
+import (
+	"os"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/uci/ucitest"
+)
+
+func TestMain(m *testing.M) {
+	ucitest.Main()
+	os.Exit(m.Run())
+}
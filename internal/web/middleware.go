@@ -0,0 +1,263 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
+)
+
+// statusRecorder wraps a ResponseWriter to capture the status code and response size
+type statusRecorder struct {
+	http.ResponseWriter
+	status    int
+	size      int
+	errorBody []byte // Start of the body for error responses
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	// Keep the start of error bodies so the log entry can say what went wrong
+	if r.status >= 400 && len(r.errorBody) < 256 {
+		remaining := 256 - len(r.errorBody)
+		if remaining > len(data) {
+			remaining = len(data)
+		}
+		r.errorBody = append(r.errorBody, data[:remaining]...)
+	}
+
+	n, err := r.ResponseWriter.Write(data)
+	r.size += n
+	return n, err
+}
+
+// WithMiddleware adds middleware to wrap the routes in, the first given outermost, and
+// returns the server for chaining
+func (s *Server) WithMiddleware(middlewares ...func(http.Handler) http.Handler) *Server {
+	s.middleware = append(s.middleware, middlewares...)
+	return s
+}
+
+// Handler wraps the routes registered on mux in the server's middleware
+func (s *Server) Handler(mux http.Handler) http.Handler {
+	handler := mux
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		handler = s.middleware[i](handler)
+	}
+	return handler
+}
+
+// Middleware returns the names of the server's middleware, outermost first
+func (s *Server) Middleware() []string {
+	names := make([]string, 0, len(s.middleware))
+	for _, middleware := range s.middleware {
+		names = append(names, middlewareName(middleware))
+	}
+	return names
+}
+
+// middlewareName derives a readable name from a middleware function, e.g. "LoggingMiddleware"
+// for the closure returned by LoggingMiddleware
+func middlewareName(middleware func(http.Handler) http.Handler) string {
+	name := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer()).Name()
+	// e.g. "github.com/zully/chess-engine/internal/web.LoggingMiddleware.func1" for a closure
+	// or "...web.(*Server).RecoveryMiddleware-fm" for a method value
+	name = strings.TrimSuffix(name[strings.LastIndex(name, "/")+1:], "-fm")
+	if before, _, found := strings.Cut(name, ".func"); found {
+		name = before
+	}
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// LoggingMiddleware logs every completed request with its status, duration and sizes
+// Each request gets a request ID which is also returned in the X-Request-ID header
+func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := newRequestID()
+			w.Header().Set("X-Request-ID", requestID)
+
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			statusCode := recorder.status
+			if statusCode == 0 {
+				statusCode = http.StatusOK
+			}
+
+			attrs := []any{
+				"requestId", requestID,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", statusCode,
+				"duration", time.Since(start),
+				"requestSize", r.ContentLength,
+				"size", recorder.size,
+			}
+
+			// Client errors are warnings, server errors are errors
+			level := slog.LevelInfo
+			switch {
+			case statusCode >= 500:
+				level = slog.LevelError
+			case statusCode >= 400:
+				level = slog.LevelWarn
+			}
+			if level != slog.LevelInfo {
+				attrs = append(attrs, "error", string(recorder.errorBody))
+			}
+
+			logger.Log(r.Context(), level, "http request", attrs...)
+		})
+	}
+}
+
+// newRequestID generates a random (version 4) UUID for correlating log entries
+func newRequestID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+
+	id[6] = (id[6] & 0x0f) | 0x40 // Version 4
+	id[8] = (id[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
+
+// RecoveryMiddleware recovers from panics in handlers, returning a 500 error envelope
+// It never touches the game: handlers that change it lock it with lockGame, which rolls a
+// half-mutated game back before the panic gets here. http.ErrAbortHandler is passed on, as it
+// asks net/http to abort the response.
+func (s *Server) RecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			slog.Error("panic in handler",
+				"requestId", w.Header().Get("X-Request-ID"),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"panic", recovered,
+				"stack", string(debug.Stack()))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "Internal server error"})
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// lockGame locks s.mu for a handler that changes the game and returns the function that
+// unlocks it, which the handler defers. The snapshot is taken once the lock is held, so it is
+// the state this request started from. If the handler panics and leaves the game inconsistent,
+// the game is rolled back before the lock is released and the panic goes on to RecoveryMiddleware.
+func (s *Server) lockGame() (unlock func()) {
+	s.mu.Lock()
+	snapshot := s.snapshotGame()
+
+	return func() {
+		defer s.mu.Unlock()
+
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		if recovered != http.ErrAbortHandler && !s.isGameConsistent() {
+			fen := s.GameBoard.ToFEN()
+			if err := s.restoreGame(snapshot); err == nil {
+				slog.Warn("game rolled back after panic", "fen", fen, "moves", len(snapshot.uciMoves))
+			} else {
+				slog.Error("failed to roll back game after panic", "fen", fen, "error", err)
+			}
+		}
+		panic(recovered)
+	}
+}
+
+// gameSnapshot is the game state RecoveryMiddleware rolls back to
+type gameSnapshot struct {
+	uciMoves    []string
+	clock       *game.GameClock
+	engineMoves map[int]game.EngineConfig
+}
+
+// snapshotGame copies the game state; the caller must hold s.mu
+// Moves are only ever appended to the board's history, so the moves are kept by reference,
+// capped so that later appends can't reach into the snapshot
+func (s *Server) snapshotGame() gameSnapshot {
+	moves := s.GameBoard.UCIMoves
+	snapshot := gameSnapshot{uciMoves: moves[:len(moves):len(moves)]}
+	if s.Clock != nil {
+		clock := *s.Clock
+		snapshot.clock = &clock
+	}
+	if s.engineMoves != nil {
+		snapshot.engineMoves = make(map[int]game.EngineConfig, len(s.engineMoves))
+		for ply, settings := range s.engineMoves {
+			snapshot.engineMoves[ply] = settings
+		}
+	}
+	return snapshot
+}
+
+// restoreGame puts back a snapshot of the game state; the caller must hold s.mu
+// The board is restored in place, as other references to it (e.g. main's) must stay valid
+func (s *Server) restoreGame(snapshot gameSnapshot) error {
+	restored, err := replayUCIMoves(snapshot.uciMoves)
+	if err != nil {
+		return err
+	}
+	*s.GameBoard = *restored
+	s.Clock = snapshot.clock
+	s.engineMoves = snapshot.engineMoves
+	return nil
+}
+
+// isGameConsistent reports whether the current board is valid and matches a replay of its move history
+func (s *Server) isGameConsistent() bool {
+	if s.GameBoard == nil || s.GameBoard.Validate() != nil {
+		return false
+	}
+
+	replayed, err := replayUCIMoves(s.GameBoard.UCIMoves)
+	if err != nil {
+		return false
+	}
+	return replayed.ToFEN() == s.GameBoard.ToFEN()
+}
+
+// replayUCIMoves builds a board by playing the given moves from the starting position
+func replayUCIMoves(uciMoves []string) (*board.Board, error) {
+	b := board.NewBoard()
+	if _, err := b.ApplyMoves(uciMoves); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,154 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
+)
+
+func TestRecoveryMiddlewareRollsBackGame(t *testing.T) {
+	gameBoard := board.NewBoard()
+	s := NewServer(gameBoard, nil)
+	defer s.Close()
+
+	// Set up a game in progress: two moves, the second by the engine, on a running clock
+	for _, move := range []string{"e2e4", "e7e5"} {
+		if err := s.GameBoard.MakeUCIMove(move); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.recordEngineMove()
+	s.Clock = game.NewGameClock(60000, 60000, 0)
+	s.Clock.Start(true)
+	wantFEN := s.GameBoard.ToFEN()
+	wantClock := *s.Clock
+
+	// A handler that half-applies a move and then panics
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer s.lockGame()()
+
+		s.GameBoard.MakeUCIMove("g1f3")
+		s.recordEngineMove()
+		s.clockMoveMade()
+		s.GameBoard.Squares[4][4].Piece = board.WQ // Not reachable by replaying the moves
+		panic("handler bug")
+	})
+
+	recorder := httptest.NewRecorder()
+	s.RecoveryMiddleware(panicking).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/move", nil))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusInternalServerError)
+	}
+	var response ErrorResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil || response.Error == "" {
+		t.Errorf("body is not an error envelope: %q (%v)", recorder.Body.String(), err)
+	}
+
+	if s.GameBoard != gameBoard {
+		t.Error("rollback replaced the board instead of restoring it in place")
+	}
+	if got := gameBoard.ToFEN(); got != wantFEN {
+		t.Errorf("board after rollback = %q, want %q", got, wantFEN)
+	}
+	if s.Clock == nil || *s.Clock != wantClock {
+		t.Errorf("clock after rollback = %+v, want %+v", s.Clock, wantClock)
+	}
+	if len(s.engineMoves) != 1 {
+		t.Errorf("engine moves after rollback = %v, want only ply 1", s.engineMoves)
+	} else if _, exists := s.engineMoves[1]; !exists {
+		t.Errorf("engine moves after rollback = %v, want ply 1", s.engineMoves)
+	}
+
+	// The server lock was released, so the next request can proceed
+	recorder = httptest.NewRecorder()
+	s.GetFEN(recorder, httptest.NewRequest(http.MethodGet, "/api/fen", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("next request status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+}
+
+func TestRecoveryKeepsMovesMadeBeforeTheLock(t *testing.T) {
+	s := NewServer(board.NewBoard(), nil)
+	defer s.Close()
+
+	// The panicking request waits before locking the game, while another request moves
+	started, proceed := make(chan struct{}), make(chan struct{})
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-proceed
+
+		defer s.lockGame()()
+		s.GameBoard.MakeUCIMove("e7e5")
+		s.GameBoard.Squares[4][4].Piece = board.WQ
+		panic("handler bug")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.RecoveryMiddleware(panicking).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/move", nil))
+	}()
+
+	<-started
+	s.mu.Lock()
+	if err := s.GameBoard.MakeUCIMove("e2e4"); err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Unlock()
+	close(proceed)
+	<-done
+
+	if got := s.GameBoard.UCIMoves; len(got) != 1 || got[0] != "e2e4" {
+		t.Errorf("moves after rollback = %v, want [e2e4]", got)
+	}
+}
+
+func TestRecoveryMiddlewareDoesNotLockTheGame(t *testing.T) {
+	s := NewServer(board.NewBoard(), nil)
+	defer s.Close()
+
+	// A long search holds the game lock; health checks must still answer
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	done := make(chan int)
+	go func() {
+		recorder := httptest.NewRecorder()
+		s.RecoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		done <- recorder.Code
+	}()
+
+	select {
+	case code := <-done:
+		if code != http.StatusOK {
+			t.Errorf("status = %d, want %d", code, http.StatusOK)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("request waited for the game lock")
+	}
+}
+
+func TestRecoveryMiddlewareRepanicsAbortHandler(t *testing.T) {
+	s := NewServer(board.NewBoard(), nil)
+	defer s.Close()
+
+	aborting := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", recovered)
+		}
+	}()
+	s.RecoveryMiddleware(aborting).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/state", nil))
+	t.Error("http.ErrAbortHandler was swallowed")
+}
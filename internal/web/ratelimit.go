@@ -0,0 +1,129 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Idle buckets are dropped after bucketIdleTTL, checked every bucketCleanupInterval
+const (
+	bucketIdleTTL         = 10 * time.Minute
+	bucketCleanupInterval = time.Minute
+)
+
+// RateLimiter limits requests per client IP with a token bucket per IP
+type RateLimiter struct {
+	rate    float64 // Tokens added per second
+	burst   float64 // Bucket capacity
+	buckets sync.Map
+
+	stop      chan struct{} // Closed by Close to end the cleanup goroutine
+	closeOnce sync.Once
+}
+
+// tokenBucket holds the tokens left for one client IP
+type tokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	lastSeen   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing reqsPerIPPerSec requests per second from each IP,
+// with bursts of up to one second's worth of requests. Idle IPs are forgotten after 10 minutes.
+// Close stops the background cleanup.
+func NewRateLimiter(reqsPerIPPerSec float64) *RateLimiter {
+	limiter := &RateLimiter{
+		rate:  reqsPerIPPerSec,
+		burst: math.Max(1, math.Ceil(reqsPerIPPerSec)),
+		stop:  make(chan struct{}),
+	}
+
+	go func() {
+		ticker := time.NewTicker(bucketCleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				limiter.cleanup(now)
+			case <-limiter.stop:
+				return
+			}
+		}
+	}()
+
+	return limiter
+}
+
+// Close stops the limiter's background cleanup; it is safe to call more than once
+func (l *RateLimiter) Close() {
+	l.closeOnce.Do(func() { close(l.stop) })
+}
+
+// allow takes a token from the IP's bucket. When none is left it returns false and how long
+// until the next token arrives.
+func (l *RateLimiter) allow(ip string, now time.Time) (bool, time.Duration) {
+	value, _ := l.buckets.LoadOrStore(ip, &tokenBucket{tokens: l.burst, lastRefill: now})
+	bucket := value.(*tokenBucket)
+
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.lastRefill).Seconds()*l.rate)
+	bucket.lastRefill = now
+	bucket.lastSeen = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+}
+
+// cleanup removes the buckets of IPs not seen within bucketIdleTTL
+func (l *RateLimiter) cleanup(now time.Time) {
+	l.buckets.Range(func(key, value any) bool {
+		bucket := value.(*tokenBucket)
+		bucket.mu.Lock()
+		idle := now.Sub(bucket.lastSeen) > bucketIdleTTL
+		bucket.mu.Unlock()
+
+		if idle {
+			l.buckets.Delete(key)
+		}
+		return true
+	})
+}
+
+// RateLimitMiddleware rejects requests over the limiter's per-IP rate with 429 Too Many
+// Requests and a Retry-After header. Use one limiter per group of routes sharing a limit.
+func RateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, retryAfter := limiter.allow(clientIP(r), time.Now())
+			if !allowed {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", fmt.Sprintf("%d", int(math.Ceil(retryAfter.Seconds()))))
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(ErrorResponse{Error: "Too many requests"})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the IP address of the client, without the port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
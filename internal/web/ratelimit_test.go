@@ -0,0 +1,50 @@
+package web
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter := NewRateLimiter(2)
+	defer limiter.Close()
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if allowed, _ := limiter.allow("192.0.2.1", now); !allowed {
+			t.Fatalf("request %d of the burst was limited", i+1)
+		}
+	}
+	allowed, retryAfter := limiter.allow("192.0.2.1", now)
+	if allowed || retryAfter <= 0 || retryAfter > 500*time.Millisecond {
+		t.Errorf("request over the burst: allowed %v, retry after %v", allowed, retryAfter)
+	}
+	if allowed, _ := limiter.allow("192.0.2.2", now); !allowed {
+		t.Error("another IP was limited")
+	}
+	if allowed, _ := limiter.allow("192.0.2.1", now.Add(500*time.Millisecond)); !allowed {
+		t.Error("request after the refill was limited")
+	}
+}
+
+func TestRateLimiterCloseStopsCleanup(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	limiters := make([]*RateLimiter, 10)
+	for i := range limiters {
+		limiters[i] = NewRateLimiter(1)
+	}
+	for _, limiter := range limiters {
+		limiter.Close()
+		limiter.Close() // Closing twice is harmless
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines after Close, %d before", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
+)
+
+// ReplayResponse is the response of the replay endpoint
+type ReplayResponse struct {
+	Moves      int                    `json:"moves"`      // Half-moves in the bundle
+	Divergence *game.ReplayDivergence `json:"divergence"` // First engine move decided differently, null if none
+}
+
+// GetReproBundle returns the reproducibility bundle of the current game
+func (s *Server) GetReproBundle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	engineVersion := ""
+	if s.StockfishEngine != nil {
+		if version, err := s.StockfishEngine.GetEngineInfo(); err == nil {
+			engineVersion = version
+		}
+	}
+
+	s.trimEngineMoves()
+	w.Header().Set("Content-Disposition", `attachment; filename="repro.json"`)
+	json.NewEncoder(w).Encode(game.NewReproBundle(s.GameBoard, s.engineMoves, engineVersion, s.engineThreads))
+}
+
+// ReplayGame asks the engine again for every engine move of a posted reproducibility bundle
+// and reports the first one it decides differently
+func (s *Server) ReplayGame(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var bundle game.ReproBundle
+	if err := json.NewDecoder(r.Body).Decode(&bundle); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	if s.StockfishEngine == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Stockfish engine not available"})
+		return
+	}
+
+	divergence, err := s.replay(bundle)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Replay failed: %v", err)})
+		return
+	}
+
+	json.NewEncoder(w).Encode(ReplayResponse{Moves: len(bundle.Moves), Divergence: divergence})
+}
+
+// Replay asks the engine again for every engine move of a reproducibility bundle and returns
+// the first one it decides differently (nil if the replay matches)
+func (s *Server) Replay(bundle game.ReproBundle) (*game.ReplayDivergence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.StockfishEngine == nil {
+		return nil, fmt.Errorf("engine not available")
+	}
+	return s.replay(bundle)
+}
+
+// replay implements Replay. Searches with several threads don't repeat themselves, so the
+// engine searches with one thread, whatever the bundle was recorded with, and a bundle
+// recorded with more threads can diverge where the original search was not reproducible.
+// The caller must hold s.mu
+func (s *Server) replay(bundle game.ReproBundle) (*game.ReplayDivergence, error) {
+	if err := s.StockfishEngine.SetThreads(1); err != nil {
+		return nil, fmt.Errorf("failed to limit the engine to one thread: %v", err)
+	}
+
+	// Each move is searched with its recorded settings, then the configured ones are restored
+	configured := s.EngineConfig
+	defer func() {
+		s.EngineConfig = configured
+		s.applyEngineStrength()
+		if s.engineThreads > 0 {
+			if err := s.StockfishEngine.SetThreads(s.engineThreads); err != nil {
+				slog.Warn("failed to restore engine threads", "error", err)
+			}
+		}
+	}()
+
+	return game.ReplayBundle(bundle, func(b *board.Board, settings game.EngineConfig) (string, error) {
+		s.EngineConfig = settings
+		s.applyEngineStrength()
+		move, err := s.searchEngineMove(b.ToFEN(), settings.Depth, settings.MoveTimeMs)
+		if err != nil {
+			return "", err
+		}
+		return move.UCI, nil
+	})
+}
+
+// recordEngineMove remembers the settings behind the engine move just played
+func (s *Server) recordEngineMove() {
+	if s.engineMoves == nil {
+		s.engineMoves = make(map[int]game.EngineConfig)
+	}
+	s.engineMoves[len(s.GameBoard.UCIMoves)-1] = s.EngineConfig
+}
+
+// trimEngineMoves forgets the engine moves that were taken back
+func (s *Server) trimEngineMoves() {
+	for ply := range s.engineMoves {
+		if ply >= len(s.GameBoard.UCIMoves) {
+			delete(s.engineMoves, ply)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
+	"github.com/zully/chess-engine/internal/uci/ucitest"
+)
+
+func TestReplayRecordedGame(t *testing.T) {
+	s := NewServer(board.NewBoard(), startFakeEngine(t, ucitest.Config{}))
+	defer s.Close()
+	s.SetEngineResources(4, 0)
+
+	for _, move := range []string{"e2e4", "", "d2d4", ""} {
+		recorder := httptest.NewRecorder()
+		if move == "" {
+			s.EngineMove(recorder, httptest.NewRequest(http.MethodPost, "/api/engine/move", nil))
+		} else {
+			s.MakeMove(recorder, httptest.NewRequest(http.MethodPost, "/api/move", strings.NewReader(`{"move":"`+move+`"}`)))
+		}
+	}
+	if len(s.GameBoard.UCIMoves) != 4 {
+		t.Fatalf("game moves = %v, want 4", s.GameBoard.UCIMoves)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.GetReproBundle(recorder, httptest.NewRequest(http.MethodGet, "/api/game/repro", nil))
+	var bundle game.ReproBundle
+	if err := json.NewDecoder(recorder.Body).Decode(&bundle); err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Threads != 4 {
+		t.Errorf("bundle records %d threads, want 4", bundle.Threads)
+	}
+
+	divergence, err := s.Replay(bundle)
+	if err != nil || divergence != nil {
+		t.Fatalf("Replay = %+v, %v, want no divergence", divergence, err)
+	}
+
+	// A different recorded engine move is reported where it was played
+	bundle.Moves[3].UCI = "a7a6"
+	divergence, err = s.Replay(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if divergence == nil || divergence.Ply != 3 || divergence.Expected != "a7a6" || divergence.Got != s.GameBoard.UCIMoves[3] {
+		t.Errorf("divergence = %+v, want the engine's %s instead of a7a6 at ply 3", divergence, s.GameBoard.UCIMoves[3])
+	}
+}
@@ -0,0 +1,56 @@
+package web
+
+import "net/http"
+
+// Per-IP request rates for the rate-limited route groups
+const (
+	engineRequestsPerSec = 2
+	stateRequestsPerSec  = 20
+)
+
+// Router is what the routes are registered on, e.g. an *http.ServeMux
+type Router interface {
+	Handle(pattern string, handler http.Handler)
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// SetupRoutes registers the page, static files and every API endpoint on mux
+func (s *Server) SetupRoutes(mux Router) {
+	// Serve static files (CSS, JS)
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(s.StaticDir))))
+
+	// Engine searches are expensive, so they get a much lower per-IP rate than state queries
+	engineLimiter, stateLimiter := NewRateLimiter(engineRequestsPerSec), NewRateLimiter(stateRequestsPerSec)
+	s.rateLimiters = append(s.rateLimiters, engineLimiter, stateLimiter)
+	engineLimit := RateLimitMiddleware(engineLimiter)
+	stateLimit := RateLimitMiddleware(stateLimiter)
+
+	// API endpoints
+	mux.Handle("/api/state", stateLimit(http.HandlerFunc(s.GetGameState)))
+	mux.HandleFunc("/api/move", s.MakeMove)
+	mux.Handle("/api/engine", engineLimit(http.HandlerFunc(s.EngineMove)))
+	mux.HandleFunc("/api/engine/config", s.EngineConfigHandler)
+	mux.HandleFunc("/api/engines", s.GetEngines)
+	mux.Handle("/api/analysis", engineLimit(http.HandlerFunc(s.GetEngineAnalysis)))
+	mux.HandleFunc("/api/undo", s.UndoMove)
+	mux.HandleFunc("/api/undo/", s.UndoMove)
+	mux.HandleFunc("/api/reset", s.ResetGame)
+	mux.Handle("/api/history", stateLimit(http.HandlerFunc(s.GetHistory)))
+	mux.Handle("/api/history/analyze", engineLimit(http.HandlerFunc(s.AnalyzeHistory)))
+	mux.Handle("/api/game/annotate", engineLimit(http.HandlerFunc(s.AnnotateGame)))
+	mux.HandleFunc("/api/game/annotate/", s.AnnotationJob)
+	mux.HandleFunc("/api/jobs/", s.Job)
+	mux.HandleFunc("/api/game/repro", s.GetReproBundle)
+	mux.Handle("/api/game/replay", engineLimit(http.HandlerFunc(s.ReplayGame)))
+	mux.HandleFunc("/api/clock/config", s.ClockConfig)
+	mux.HandleFunc("/api/clock/start", s.StartClock)
+	mux.HandleFunc("/api/validate/moves", s.ValidateMoves)
+	mux.Handle("/api/fen", stateLimit(http.HandlerFunc(s.GetFEN)))
+	mux.Handle("/api/position/attackmap", stateLimit(http.HandlerFunc(s.GetAttackMap)))
+	mux.HandleFunc("/api/webhook", s.Webhook)
+	mux.HandleFunc("/api/schema", s.GetSchema)
+	mux.HandleFunc("/health", s.Health)
+
+	// Main page
+	mux.HandleFunc("/", s.HomePage)
+}
@@ -0,0 +1,80 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+func TestRoutes(t *testing.T) {
+	// No engine: engine-backed endpoints must still answer, reporting it as unavailable
+	s := NewServer(board.NewBoard(), nil)
+	s.TemplateDir = "../../web/templates/"
+	s.StaticDir = "../../web/static/"
+	defer s.Close()
+
+	mux := http.NewServeMux()
+	s.SetupRoutes(mux)
+
+	// Run in order against the same game
+	tests := []struct {
+		method, path, body string
+		wantStatus         int
+		wantBody           string // Substring of the response body
+	}{
+		{"GET", "/", "", http.StatusOK, "<html"},
+		{"GET", "/static/chess.css", "", http.StatusOK, ""},
+		{"GET", "/api/state", "", http.StatusOK, `"legalMoveCount":20`},
+		{"GET", "/api/move", "", http.StatusMethodNotAllowed, ""},
+		{"POST", "/api/move", `{"move": "e2e4"}`, http.StatusOK, `"lastUCIMove":"e2e4"`},
+		{"POST", "/api/move", `{"move": "e2e5"}`, http.StatusOK, "Invalid move"},
+		{"POST", "/api/engine", "", http.StatusOK, "Stockfish engine not available"},
+		{"GET", "/api/engine/config", "", http.StatusOK, `"depth"`},
+		{"GET", "/api/engines", "", http.StatusOK, "[]"},
+		{"POST", "/api/analysis", "{}", http.StatusOK, "Stockfish engine not available"},
+		{"GET", "/api/fen", "", http.StatusOK, `"uciMoves":["e2e4"]`},
+		{"GET", "/api/position/attackmap", "", http.StatusOK, ""},
+		{"GET", "/api/history", "", http.StatusOK, `"san":"e4"`},
+		{"POST", "/api/history/analyze", "", http.StatusServiceUnavailable, ""},
+		{"POST", "/api/game/annotate", "{}", http.StatusServiceUnavailable, ""},
+		{"GET", "/api/game/annotate/missing", "", http.StatusNotFound, "Job not found"},
+		{"GET", "/api/jobs/missing", "", http.StatusNotFound, "Job not found"},
+		{"GET", "/api/game/repro", "", http.StatusOK, `"e2e4"`},
+		{"POST", "/api/game/replay", `{"moves": []}`, http.StatusServiceUnavailable, ""},
+		{"POST", "/api/clock/start", "", http.StatusConflict, "Clock not configured"},
+		{"POST", "/api/clock/config", `{"white": 60000, "black": 60000}`, http.StatusOK, ""},
+		{"POST", "/api/clock/start", "", http.StatusOK, ""},
+		{"POST", "/api/validate/moves", `{"moves": ["e4", "e5", "Ke3"]}`, http.StatusOK, `"validCount":2`},
+		{"GET", "/api/webhook", "", http.StatusOK, `"registered":false`},
+		{"GET", "/api/schema", "", http.StatusOK, `"/api/move"`},
+		{"GET", "/health", "", http.StatusOK, `"status":"ok"`},
+		{"POST", "/api/undo", "", http.StatusOK, ""},
+		{"POST", "/api/undo/3", "", http.StatusOK, "No moves to undo"},
+		{"POST", "/api/undo/x", "", http.StatusBadRequest, ""},
+		{"POST", "/api/reset", "", http.StatusOK, ""},
+	}
+
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+		// A client per request, so the per-IP rate limits don't interfere
+		req.RemoteAddr = fmt.Sprintf("192.0.2.%d:1234", i+1)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+
+		if recorder.Code != test.wantStatus {
+			t.Errorf("%s %s: status = %d, want %d (body %s)", test.method, test.path, recorder.Code, test.wantStatus, recorder.Body)
+			continue
+		}
+		if !strings.Contains(recorder.Body.String(), test.wantBody) {
+			t.Errorf("%s %s: body %s does not contain %s", test.method, test.path, recorder.Body, test.wantBody)
+		}
+		if strings.HasPrefix(test.path, "/api/") && test.wantStatus == http.StatusOK &&
+			!strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/json") {
+			t.Errorf("%s %s: Content-Type = %q", test.method, test.path, recorder.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
+	"github.com/zully/chess-engine/internal/jobs"
+	"github.com/zully/chess-engine/internal/uci"
+)
+
+// apiOperation describes one endpoint in the API schema
+type apiOperation struct {
+	Path        string
+	Method      string
+	Summary     string
+	Request     interface{}         // Request body type (nil if none)
+	Response    interface{}         // Response body type (nil for a free-form object)
+	QueryParams map[string]apiParam // Query parameters by name
+	PathParams  map[string]apiParam // Path parameters by name
+}
+
+// apiParam describes a path or query parameter
+type apiParam struct {
+	Type        string // JSON schema type: "integer", "string" or "boolean"
+	Description string
+}
+
+// ErrorResponse is the error envelope returned by endpoints that don't return a game state
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// HistoryResponse is the response of the history endpoint
+type HistoryResponse struct {
+	Moves   []game.HistoryEntry `json:"moves"`
+	Pending bool                `json:"pending"`         // Some evaluations are still being computed; ask again later
+	JobID   string              `json:"jobId,omitempty"` // Background job computing them, see GET /api/jobs/{id}
+}
+
+// FENResponse is the response of the FEN endpoint
+type FENResponse struct {
+	FEN        string   `json:"fen"`
+	MoveNumber int      `json:"moveNumber"`
+	SideToMove string   `json:"sideToMove"` // "white" or "black"
+	UCIMoves   []string `json:"uciMoves"`   // Moves from the start position, for "position startpos moves ..."
+}
+
+// JobSubmitted is the response of endpoints that start a background job
+type JobSubmitted struct {
+	ID string `json:"id"` // Poll GET /api/jobs/{id} for progress and the result
+}
+
+// ClockConfigRequest is the request of the clock configuration endpoint, in milliseconds
+type ClockConfigRequest struct {
+	White     int64 `json:"white"`
+	Black     int64 `json:"black"`
+	Increment int64 `json:"increment"`
+}
+
+// HealthResponse is the response of the health endpoint
+type HealthResponse struct {
+	Status        string   `json:"status"` // Always "ok" when the server answers
+	EngineHealthy bool     `json:"engineHealthy"`
+	Middleware    []string `json:"middleware"` // Middleware wrapped around the routes, outermost first
+}
+
+// MoveListRequest is the request of the move list validation endpoint
+type MoveListRequest struct {
+	Moves []string `json:"moves"` // Moves from the start position, in UCI or algebraic notation
+}
+
+// MoveValidationResponse reports which moves of a list could be played
+type MoveValidationResponse struct {
+	ValidCount int               `json:"validCount"`
+	Errors     []board.MoveError `json:"errors"`
+}
+
+// AnnotateRequest is the request of the game review endpoint
+type AnnotateRequest struct {
+	Moves []string `json:"moves,omitempty"` // UCI moves from the start position; defaults to the current game
+	Depth int      `json:"depth,omitempty"` // Search depth per position, 1-20 (default 12)
+}
+
+// AnnotateResponse is the result of a game review job
+type AnnotateResponse struct {
+	Moves []game.AnnotatedMove `json:"moves"`
+}
+
+// apiOperations lists every API endpoint - keep in sync with SetupRoutes and SetupDebugRoutes
+var apiOperations = []apiOperation{
+	{Path: "/api/state", Method: http.MethodGet, Summary: "Current game state", Response: game.GameState{},
+		QueryParams: map[string]apiParam{"includeAttackMap": {"boolean", "Set to true to include the attack map"}}},
+	{Path: "/api/move", Method: http.MethodPost, Summary: "Make a move in UCI notation", Request: game.MoveRequest{}, Response: game.GameState{}},
+	{Path: "/api/engine", Method: http.MethodPost, Summary: "Let the engine play a move using the engine config", Response: game.GameState{}},
+	{Path: "/api/engine/config", Method: http.MethodGet, Summary: "Current engine strength settings", Response: game.EngineConfig{}},
+	{Path: "/api/engine/config", Method: http.MethodPost, Summary: "Update engine strength settings", Request: game.EngineConfig{}, Response: game.EngineConfig{}},
+	{Path: "/api/engines", Method: http.MethodGet, Summary: "Configured engines with their health status", Response: []EngineStatus{}},
+	{Path: "/api/analysis", Method: http.MethodPost, Summary: "Multi-line engine analysis of the current position", Request: game.EngineRequest{}},
+	{Path: "/api/undo", Method: http.MethodPost, Summary: "Undo the last move", Response: game.GameState{}},
+	{Path: "/api/undo/{n}", Method: http.MethodPost, Summary: "Undo the last n half-moves", Response: game.GameState{},
+		PathParams: map[string]apiParam{"n": {"integer", "Number of half-moves to undo"}}},
+	{Path: "/api/reset", Method: http.MethodPost, Summary: "Start a new game", Response: game.GameState{}},
+	{Path: "/api/history", Method: http.MethodGet, Summary: "Move history with evaluations", Response: HistoryResponse{},
+		QueryParams: map[string]apiParam{"from": {"integer", "First move number to include"}, "to": {"integer", "Last move number to include"}}},
+	{Path: "/api/history/analyze", Method: http.MethodPost, Summary: "Start a background full-game analysis; the job result is a HistoryResponse", Response: JobSubmitted{}},
+	{Path: "/api/game/annotate", Method: http.MethodPost, Summary: "Start a background game review classifying every move; the job result is an AnnotateResponse",
+		Request: AnnotateRequest{}, Response: JobSubmitted{}},
+	{Path: "/api/game/annotate/{id}", Method: http.MethodGet, Summary: "Status, progress and result of a game review job", Response: jobs.Job{},
+		PathParams: map[string]apiParam{"id": {"string", "Job ID"}}},
+	{Path: "/api/jobs/{id}", Method: http.MethodGet, Summary: "Status, progress and result of a background job", Response: jobs.Job{},
+		PathParams: map[string]apiParam{"id": {"string", "Job ID"}}},
+	{Path: "/api/jobs/{id}", Method: http.MethodDelete, Summary: "Cancel a background job", Response: jobs.Job{},
+		PathParams: map[string]apiParam{"id": {"string", "Job ID"}}},
+	{Path: "/api/game/repro", Method: http.MethodGet, Summary: "Reproducibility bundle of the current game: moves with the engine settings behind each engine move", Response: game.ReproBundle{}},
+	{Path: "/api/game/replay", Method: http.MethodPost, Summary: "Replay the engine moves of a reproducibility bundle and report the first divergence", Request: game.ReproBundle{}, Response: ReplayResponse{}},
+	{Path: "/api/clock/config", Method: http.MethodPost, Summary: "Set up a stopped clock for the current game", Request: ClockConfigRequest{}, Response: game.ClockState{}},
+	{Path: "/api/clock/start", Method: http.MethodPost, Summary: "Start the clock of the side to move", Response: game.ClockState{}},
+	{Path: "/api/validate/moves", Method: http.MethodGet, Summary: "Check a move list (e.g. from a PGN) and report every move that can't be played; also accepts POST",
+		Request: MoveListRequest{}, Response: MoveValidationResponse{}},
+	{Path: "/api/fen", Method: http.MethodGet, Summary: "Current position as FEN plus the UCI move list", Response: FENResponse{}},
+	{Path: "/api/position/attackmap", Method: http.MethodGet, Summary: "Squares attacked by each side in the current position", Response: game.AttackMap{}},
+	{Path: "/api/webhook", Method: http.MethodGet, Summary: "Registered move webhook", Response: WebhookStatus{}},
+	{Path: "/api/webhook", Method: http.MethodPost, Summary: "Register a webhook called (HMAC-signed) after every move", Request: WebhookRequest{}, Response: WebhookStatus{}},
+	{Path: "/api/webhook", Method: http.MethodDelete, Summary: "Remove the move webhook", Response: WebhookStatus{}},
+	{Path: "/api/schema", Method: http.MethodGet, Summary: "This OpenAPI document"},
+	{Path: "/health", Method: http.MethodGet, Summary: "Server and engine health, with the middleware in use", Response: HealthResponse{}},
+
+	// Registered by SetupDebugRoutes
+	{Path: "/api/engine/benchmark", Method: http.MethodGet, Summary: "Debug mode only: search the starting position for 5 seconds and report the engine's speed; also accepts POST",
+		Response: uci.BenchmarkResult{}},
+	{Path: "/api/engine/benchmark/perft/{depth}", Method: http.MethodGet, Summary: "Debug mode only: count the move tree of the starting position with our move generator",
+		Response: PerftResponse{}, PathParams: map[string]apiParam{"depth": {"integer", "Perft depth, 1-5"}}},
+}
+
+// GetSchema serves an OpenAPI 3 document describing the API
+func (s *Server) GetSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	json.NewEncoder(w).Encode(BuildSchema())
+}
+
+// BuildSchema generates the OpenAPI document from the operation table and the Go types
+func BuildSchema() map[string]interface{} {
+	components := make(map[string]interface{})
+	errorContent := jsonContent(schemaFor(reflect.TypeOf(ErrorResponse{}), components))
+
+	paths := make(map[string]interface{})
+	for _, op := range apiOperations {
+		operation := map[string]interface{}{
+			"summary": op.Summary,
+		}
+
+		params := []interface{}{}
+		for name, param := range op.PathParams {
+			params = append(params, map[string]interface{}{
+				"name":        name,
+				"in":          "path",
+				"required":    true,
+				"description": param.Description,
+				"schema":      map[string]interface{}{"type": param.Type},
+			})
+		}
+		for name, param := range op.QueryParams {
+			params = append(params, map[string]interface{}{
+				"name":        name,
+				"in":          "query",
+				"description": param.Description,
+				"schema":      map[string]interface{}{"type": param.Type},
+			})
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+
+		if op.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"content": jsonContent(schemaFor(reflect.TypeOf(op.Request), components)),
+			}
+		}
+
+		responseSchema := map[string]interface{}{"type": "object"}
+		if op.Response != nil {
+			responseSchema = schemaFor(reflect.TypeOf(op.Response), components)
+		}
+		operation["responses"] = map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "Success (failures are reported in the \"error\" field)",
+				"content":     jsonContent(responseSchema),
+			},
+			"400": map[string]interface{}{"description": "Invalid request", "content": errorContent},
+			"405": map[string]interface{}{"description": "Method not allowed (plain text)"},
+			"429": map[string]interface{}{"description": "Too many requests from this client (rate-limited routes)", "content": errorContent},
+			"500": map[string]interface{}{"description": "Internal server error", "content": errorContent},
+		}
+
+		pathItem, exists := paths[op.Path].(map[string]interface{})
+		if !exists {
+			pathItem = make(map[string]interface{})
+			paths[op.Path] = pathItem
+		}
+		pathItem[strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Chess Engine API",
+			"version": "1.0.0",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": components},
+	}
+}
+
+// jsonContent wraps a schema in an application/json content entry
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// schemaFor returns the JSON schema for a Go type, registering named structs as components
+func schemaFor(t reflect.Type, components map[string]interface{}) map[string]interface{} {
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), components)
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), components)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), components)}
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+		if _, exists := components[t.Name()]; exists {
+			return ref
+		}
+		components[t.Name()] = nil // Reserve the name so recursive types terminate
+
+		properties := make(map[string]interface{})
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // Unexported
+			}
+
+			name := field.Name
+			omitEmpty := false
+			if tag := field.Tag.Get("json"); tag != "" {
+				parts := strings.Split(tag, ",")
+				if parts[0] == "-" {
+					continue
+				}
+				if parts[0] != "" {
+					name = parts[0]
+				}
+				for _, option := range parts[1:] {
+					if option == "omitempty" {
+						omitEmpty = true
+					}
+				}
+			}
+
+			properties[name] = schemaFor(field.Type, components)
+			if !omitEmpty {
+				required = append(required, name)
+			}
+		}
+
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		components[t.Name()] = schema
+		return ref
+	default:
+		return map[string]interface{}{}
+	}
+}
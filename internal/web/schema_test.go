@@ -0,0 +1,131 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// routeRecorder is a Router that records the patterns registered on its ServeMux
+type routeRecorder struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func (r *routeRecorder) Handle(pattern string, handler http.Handler) {
+	r.patterns = append(r.patterns, pattern)
+	r.ServeMux.Handle(pattern, handler)
+}
+
+func (r *routeRecorder) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	r.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// pathParam matches a path parameter such as {id} in a schema path
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+func TestSchemaCoversRoutes(t *testing.T) {
+	s := NewServer(board.NewBoard(), nil)
+	defer s.Close()
+	mux := &routeRecorder{ServeMux: http.NewServeMux()}
+	s.SetupRoutes(mux)
+	s.SetupDebugRoutes(mux)
+
+	if len(mux.patterns) < 20 {
+		t.Fatalf("only %d routes registered: %v", len(mux.patterns), mux.patterns)
+	}
+
+	for _, pattern := range mux.patterns {
+		if !strings.HasPrefix(pattern, "/api/") && pattern != "/health" {
+			continue // Page and static files
+		}
+
+		documented := false
+		for _, op := range apiOperations {
+			if strings.HasSuffix(pattern, "/") {
+				// A subtree such as /api/jobs/ is documented with a parameter, e.g. /api/jobs/{id}
+				documented = documented || (strings.HasPrefix(op.Path, pattern) && pathParam.MatchString(op.Path))
+			} else {
+				documented = documented || op.Path == pattern
+			}
+		}
+		if !documented {
+			t.Errorf("route %s is missing from apiOperations", pattern)
+		}
+	}
+
+	// And every documented path must be served by its own route, not the home page
+	for _, op := range apiOperations {
+		path := pathParam.ReplaceAllString(op.Path, "1")
+		if _, registered := mux.Handler(httptest.NewRequest(op.Method, path, nil)); registered == "/" {
+			t.Errorf("documented path %s has no route", op.Path)
+		}
+	}
+}
+
+func TestSchemaParametersAndErrors(t *testing.T) {
+	// Round trip through JSON, as clients see it
+	data, err := json.Marshal(BuildSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name   string
+				In     string
+				Schema struct{ Type string }
+			}
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema struct {
+						Ref string `json:"$ref"`
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	paramTypes := []struct{ path, method, name, want string }{
+		{"/api/jobs/{id}", "get", "id", "string"},
+		{"/api/jobs/{id}", "delete", "id", "string"},
+		{"/api/game/annotate/{id}", "get", "id", "string"},
+		{"/api/state", "get", "includeAttackMap", "boolean"},
+		{"/api/undo/{n}", "post", "n", "integer"},
+		{"/api/history", "get", "from", "integer"},
+		{"/api/engine/benchmark/perft/{depth}", "get", "depth", "integer"},
+	}
+	for _, tt := range paramTypes {
+		found := false
+		for _, param := range schema.Paths[tt.path][tt.method].Parameters {
+			if param.Name == tt.name {
+				found = true
+				if param.Schema.Type != tt.want {
+					t.Errorf("%s %s parameter %s has type %q, want %q", tt.method, tt.path, tt.name, param.Schema.Type, tt.want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s %s has no parameter %s", tt.method, tt.path, tt.name)
+		}
+	}
+
+	const errorRef = "#/components/schemas/ErrorResponse"
+	for path, operations := range schema.Paths {
+		for method, operation := range operations {
+			for _, status := range []string{"400", "500"} {
+				if ref := operation.Responses[status].Content["application/json"].Schema.Ref; ref != errorRef {
+					t.Errorf("%s %s response %s refers to %q, want %q", method, path, status, ref, errorRef)
+				}
+			}
+		}
+	}
+}
@@ -2,9 +2,9 @@ package web
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/zully/chess-engine/internal/board"
+	"github.com/zully/chess-engine/internal/game"
 	"github.com/zully/chess-engine/internal/uci"
 )
 
@@ -38,71 +38,40 @@ func IsValidUCIMove(move string) bool {
 	return true
 }
 
-// ConvertUCIToAlgebraic converts a UCI move to algebraic notation (simplified)
-func ConvertUCIToAlgebraic(uciMove string, gameBoard *board.Board, isFirstMove bool) string {
-	if len(uciMove) < 4 {
-		return uciMove
-	}
-
-	// Simple algebraic conversion without creating board copies
-	// This prevents additional position recording that was causing false repetitions
-
-	// Handle castling moves
-	if uciMove == "e1g1" || uciMove == "e8g8" {
-		return "O-O"
-	}
-	if uciMove == "e1c1" || uciMove == "e8c8" {
-		return "O-O-O"
-	}
-
-	// For other moves, return a simplified format
-	toSquare := uciMove[2:4]
-
-	// Check if there's a piece on the destination (capture)
-	toRank, toFile := board.GetSquareCoords(toSquare)
-	if toRank >= 0 && toRank <= 7 && toFile >= 0 && toFile <= 7 {
-		targetPiece := gameBoard.GetPiece(toRank, toFile)
-		if targetPiece != board.Empty {
-			// It's a capture - add 'x'
-			result := toSquare
-			if len(uciMove) == 5 {
-				result += "=" + strings.ToUpper(string(uciMove[4]))
-			}
-			return result
+// ConvertPVToAlgebraic converts a principal variation from UCI to SAN by playing it out
+// on a copy of the board. Moves after one that can't be played are left in UCI notation.
+func ConvertPVToAlgebraic(pv []string, gameBoard *board.Board) []string {
+	algebraicMoves := make([]string, len(pv))
+	copy(algebraicMoves, pv)
+
+	replay := gameBoard.Clone()
+	for i, uciMove := range pv {
+		san, err := replay.SAN(uciMove)
+		if err != nil {
+			break
 		}
+		algebraicMoves[i] = san
+		replay.MakeUCIMove(uciMove)
 	}
-
-	// Regular move
-	result := toSquare
-	if len(uciMove) == 5 {
-		result += "=" + strings.ToUpper(string(uciMove[4]))
-	}
-	return result
+	return algebraicMoves
 }
 
-// GetEvaluationAfterMove gets the position evaluation after making a move
-func GetEvaluationAfterMove(board *board.Board, uciMove string, stockfishEngine *uci.Engine) (int, error) {
+// GetEvaluationAfterMove evaluates the position after a move, in centipawns from White's
+// perspective like every other evaluation. The board is not modified.
+func GetEvaluationAfterMove(gameBoard *board.Board, uciMove string, stockfishEngine *uci.Engine) (int, error) {
 	if stockfishEngine == nil {
 		return 0, fmt.Errorf("engine not available")
 	}
 
-	// Create FEN directly without making the move on a board copy
-	// This avoids polluting the position history
-	currentFEN := board.ToFEN()
-
-	// Use Stockfish to evaluate the position after the move
-	// Set the current position and get the evaluation after the move
-	if err := stockfishEngine.SetPosition(currentFEN); err != nil {
+	// Play the move on a copy so the game's position history is left alone
+	next := gameBoard.Clone()
+	if err := next.MakeUCIMove(uciMove); err != nil {
 		return 0, err
 	}
 
-	// Get the current position evaluation
-	// This avoids board copy pollution while still providing evaluation data
-	currentEval, err := stockfishEngine.GetEvaluation(currentFEN)
-	if err != nil {
-		return 0, err
+	eval, _, ok := game.EvaluatePosition(next, stockfishEngine)
+	if !ok {
+		return 0, fmt.Errorf("evaluation failed")
 	}
-
-	// Return negative since we're looking from opponent's perspective
-	return -currentEval, nil
+	return eval, nil
 } 
\ No newline at end of file
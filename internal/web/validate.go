@@ -0,0 +1,31 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+// ValidateMoves replays a move list from the start position and reports every move that can't be played
+func (s *Server) ValidateMoves(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req MoveListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	validCount, moveErrors := board.NewBoard().ValidateMoveList(req.Moves)
+	if moveErrors == nil {
+		moveErrors = []board.MoveError{}
+	}
+	json.NewEncoder(w).Encode(MoveValidationResponse{ValidCount: validCount, Errors: moveErrors})
+}
@@ -0,0 +1,264 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/zully/chess-engine/internal/game"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the webhook body, keyed by the shared secret
+const SignatureHeader = "X-Chess-Signature"
+
+// Webhook delivery retry settings
+const (
+	webhookMaxAttempts    = 4
+	webhookInitialBackoff = 500 * time.Millisecond
+	webhookTimeout        = 5 * time.Second
+	webhookQueueSize      = 64 // Deliveries waiting to be sent; more are dropped
+)
+
+// WebhookRequest registers a URL to be called whenever a move is made or the game ends
+type WebhookRequest struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// WebhookStatus describes the registered webhook (the secret is never echoed back)
+type WebhookStatus struct {
+	URL        string `json:"url"`
+	Registered bool   `json:"registered"`
+}
+
+// WebhookPayload is the JSON body delivered to the webhook
+type WebhookPayload struct {
+	Ply      int              `json:"ply"`
+	FEN      string           `json:"fen"`
+	LastMove string           `json:"lastMove"` // UCI notation
+	LastSAN  string           `json:"lastSan"`
+	GameOver bool             `json:"gameOver"`
+	Events   []game.GameEvent `json:"events"`
+}
+
+// webhook delivers signed payloads to a registered URL with retry and backoff
+// Payloads are delivered one at a time from a queue, so retries never reorder plies
+type webhook struct {
+	url     string
+	secret  string
+	client  *http.Client
+	backoff time.Duration
+	queue   chan WebhookPayload
+}
+
+// newWebhook creates a webhook and starts its delivery goroutine, which runs until stop
+// Unless allowPrivate is set, connections to loopback, private and link-local addresses are refused
+func newWebhook(url, secret string, allowPrivate bool) *webhook {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		// Checked when connecting, so a host can't resolve to a public address at registration and a private one later
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateAddress(ip) {
+				return fmt.Errorf("webhook address %s is not public", host)
+			}
+			return nil
+		}
+	}
+
+	wh := &webhook{
+		url:     url,
+		secret:  secret,
+		client:  &http.Client{Timeout: webhookTimeout, Transport: &http.Transport{DialContext: dialer.DialContext}},
+		backoff: webhookInitialBackoff,
+		queue:   make(chan WebhookPayload, webhookQueueSize),
+	}
+	go wh.run()
+	return wh
+}
+
+// run delivers the queued payloads in order until the queue is closed
+func (wh *webhook) run() {
+	for payload := range wh.queue {
+		if err := wh.deliver(payload); err != nil {
+			slog.Warn("webhook delivery failed", "url", wh.url, "ply", payload.Ply, "error", err)
+		}
+	}
+}
+
+// enqueue queues a payload for delivery, dropping it if the queue is full so moves never wait on the webhook
+func (wh *webhook) enqueue(payload WebhookPayload) {
+	select {
+	case wh.queue <- payload:
+	default:
+		slog.Warn("webhook queue full, dropping delivery", "url", wh.url, "ply", payload.Ply)
+	}
+}
+
+// stop ends delivery once the queued payloads have been sent; the webhook can't be used afterwards
+func (wh *webhook) stop() {
+	close(wh.queue)
+}
+
+// isPrivateAddress reports whether ip is a loopback, private, link-local or unspecified address
+func isPrivateAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
+
+// checkWebhookHost resolves a webhook host and rejects it if any of its addresses is not public
+func checkWebhookHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s", host)
+	}
+	for _, addr := range addrs {
+		if isPrivateAddress(addr.IP) {
+			return fmt.Errorf("url must not point to a loopback, private or link-local address")
+		}
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 signature of body using secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver posts the payload, retrying with exponential backoff until it is accepted
+func (wh *webhook) deliver(payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	signature := "sha256=" + Sign(wh.secret, body)
+
+	backoff := wh.backoff
+	for attempt := 1; ; attempt++ {
+		err = wh.post(body, signature)
+		if err == nil || attempt == webhookMaxAttempts {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post makes a single delivery attempt
+func (wh *webhook) post(body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, wh.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// notifyWebhook queues the state for the registered webhook so delivery never blocks
+// move handling; the caller must hold s.mu
+func (s *Server) notifyWebhook(state game.GameState) {
+	if s.webhook == nil {
+		return
+	}
+
+	payload := WebhookPayload{
+		Ply:      len(s.GameBoard.UCIMoves),
+		FEN:      s.GameBoard.ToFEN(),
+		GameOver: state.GameOver,
+		Events:   state.Events,
+	}
+	if payload.Ply > 0 {
+		payload.LastMove = s.GameBoard.UCIMoves[payload.Ply-1]
+		payload.LastSAN = s.GameBoard.MovesPlayed[payload.Ply-1]
+	}
+	s.webhook.enqueue(payload)
+}
+
+// setWebhook replaces the registered webhook, stopping the old one; the caller must hold s.mu
+func (s *Server) setWebhook(wh *webhook) {
+	if s.webhook != nil {
+		s.webhook.stop()
+	}
+	s.webhook = wh
+}
+
+func (s *Server) Webhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		status := WebhookStatus{}
+		if s.webhook != nil {
+			status = WebhookStatus{URL: s.webhook.url, Registered: true}
+		}
+		json.NewEncoder(w).Encode(status)
+	case http.MethodPost:
+		var req WebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		target, err := url.Parse(req.URL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "url must be an absolute http(s) URL"})
+			return
+		}
+		if req.Secret == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "secret is required"})
+			return
+		}
+		if !s.AllowPrivateWebhooks {
+			if err := checkWebhookHost(r.Context(), target.Hostname()); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+				return
+			}
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.setWebhook(newWebhook(req.URL, req.Secret, s.AllowPrivateWebhooks))
+		json.NewEncoder(w).Encode(WebhookStatus{URL: req.URL, Registered: true})
+	case http.MethodDelete:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.setWebhook(nil)
+		json.NewEncoder(w).Encode(WebhookStatus{})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
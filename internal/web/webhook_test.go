@@ -0,0 +1,118 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zully/chess-engine/internal/board"
+)
+
+func TestWebhookRejectsPrivateTargets(t *testing.T) {
+	s := NewServer(board.NewBoard(), nil)
+	defer s.Close()
+
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.0.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		body := `{"url": "` + target + `", "secret": "s3cret"}`
+		recorder := httptest.NewRecorder()
+		s.Webhook(recorder, httptest.NewRequest(http.MethodPost, "/api/webhook", strings.NewReader(body)))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("registering %s: status = %d, want %d", target, recorder.Code, http.StatusBadRequest)
+		}
+	}
+	if s.webhook != nil {
+		t.Error("a private webhook target was registered")
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	const secret = "s3cret"
+
+	// The receiver fails the first attempt, then records every delivery
+	var mu sync.Mutex
+	attempts := 0
+	deliveries := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		attempts++
+		first := attempts == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		deliveries <- r
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	s := NewServer(board.NewBoard(), nil)
+	s.AllowPrivateWebhooks = true
+	defer s.Close()
+
+	recorder := httptest.NewRecorder()
+	register := `{"url": "` + receiver.URL + `", "secret": "` + secret + `"}`
+	s.Webhook(recorder, httptest.NewRequest(http.MethodPost, "/api/webhook", strings.NewReader(register)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("register: status = %d, body %s", recorder.Code, recorder.Body)
+	}
+	s.webhook.backoff = time.Millisecond
+
+	moves := []string{"e2e4", "e7e5", "g1f3"}
+	for _, move := range moves {
+		recorder := httptest.NewRecorder()
+		s.MakeMove(recorder, httptest.NewRequest(http.MethodPost, "/api/move", strings.NewReader(`{"move": "`+move+`"}`)))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("move %s: status = %d", move, recorder.Code)
+		}
+	}
+
+	replay := board.NewBoard()
+	for i, move := range moves {
+		var r *http.Request
+		var body []byte
+		select {
+		case r = <-deliveries:
+			body = <-bodies
+		case <-time.After(5 * time.Second):
+			t.Fatalf("delivery %d not received", i+1)
+		}
+
+		if got, want := r.Header.Get(SignatureHeader), "sha256="+Sign(secret, body); got != want {
+			t.Errorf("delivery %d: signature = %q, want %q", i+1, got, want)
+		}
+
+		var payload WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("delivery %d: %v", i+1, err)
+		}
+		if err := replay.MakeUCIMove(move); err != nil {
+			t.Fatal(err)
+		}
+		if payload.Ply != i+1 || payload.LastMove != move || payload.FEN != replay.ToFEN() {
+			t.Errorf("delivery %d = ply %d, move %s, FEN %s; want ply %d, move %s, FEN %s",
+				i+1, payload.Ply, payload.LastMove, payload.FEN, i+1, move, replay.ToFEN())
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != len(moves)+1 {
+		t.Errorf("attempts = %d, want %d (one retry)", attempts, len(moves)+1)
+	}
+}
@@ -45,6 +45,10 @@ function setupEventListeners() {
         document.getElementById('engine-white-checkbox').addEventListener('change', handleEngineCheckboxChange);
         document.getElementById('engine-black-checkbox').addEventListener('change', handleEngineCheckboxChange);
         
+        // Engine strength selector
+        document.getElementById('elo-select').addEventListener('change', updateEngineConfig);
+        document.getElementById('style-select').addEventListener('change', updateEngineConfig);
+        
         // Analyze button
         document.getElementById('analyze-btn').addEventListener('click', requestEngineAnalysis);
         
@@ -87,6 +91,10 @@ function loadGameState() {
         .then(data => {
             gameState = data;
             updateGameState(data);
+            if (data.engineConfig) {
+                document.getElementById('elo-select').value = data.engineConfig.useElo ? data.engineConfig.elo : 0;
+                document.getElementById('style-select').value = data.engineConfig.style;
+            }
         })
         .catch(error => {
             console.error('Error loading game state:', error);
@@ -114,16 +122,15 @@ function makeMove(move) {
     });
 }
 
-function requestEngineMove() {
-    const eloSelect = document.getElementById('elo-select');
-    const selectedElo = parseInt(eloSelect.value);
-    
+function updateEngineConfig() {
+    const selectedElo = parseInt(document.getElementById('elo-select').value);
     const requestData = {
-        depth: 6,
-        elo: selectedElo
+        elo: selectedElo,
+        useElo: selectedElo > 0,
+        style: document.getElementById('style-select').value
     };
 
-    fetch('/api/engine', {
+    fetch('/api/engine/config', {
         method: 'POST',
         headers: {
             'Content-Type': 'application/json',
@@ -131,6 +138,25 @@ function requestEngineMove() {
         body: JSON.stringify(requestData)
     })
     .then(response => response.json())
+    .then(data => {
+        if (data.error) {
+            document.getElementById('game-message').textContent = 'Error: ' + data.error;
+        }
+    })
+    .catch(error => {
+        console.error('Error updating engine config:', error);
+    });
+}
+
+function requestEngineMove() {
+    fetch('/api/engine', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({})
+    })
+    .then(response => response.json())
     .then(data => {
         gameState = data;
         updateGameState(data);
@@ -302,6 +328,7 @@ function updateDisplay() {
     renderBoard();
     updateMoveHistory();
     updateGameMessage();
+    updateOpeningName();
     updateEvaluationBar();
     updateCapturedPieces();
     
@@ -884,12 +911,33 @@ function updateGameMessage() {
     } else if (gameState.positionCount >= 2) {
         message += ` (Position repeated ${gameState.positionCount} times)`;
         messageClass = 'warning';
+    } else if (gameState.fiftyMoveCounter >= 80) {
+        // Warn over the last ten moves before the fifty-move rule applies
+        const movesLeft = Math.ceil((100 - gameState.fiftyMoveCounter) / 2);
+        message += movesLeft > 0
+            ? ` (Warning: 50-move rule in effect in ${movesLeft} moves)`
+            : ' (50-move rule - draw available!)';
+        messageClass = 'warning';
     }
     
     messageDiv.className = `message ${messageClass}`;
     messageDiv.textContent = message;
 }
 
+function updateOpeningName() {
+    const openingDiv = document.getElementById('opening-name');
+    const plies = (gameState.board && gameState.board.UCIMoves) ? gameState.board.UCIMoves.length : 0;
+
+    // Only name the opening once both sides have played three moves
+    if (gameState.opening && plies >= 6) {
+        openingDiv.textContent = `${gameState.eco} ${gameState.opening}`;
+        openingDiv.style.display = 'block';
+    } else {
+        openingDiv.textContent = '';
+        openingDiv.style.display = 'none';
+    }
+}
+
 function updateButtonStates() {
     const undoBtn = document.getElementById('undo-btn');
     